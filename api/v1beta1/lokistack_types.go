@@ -18,6 +18,7 @@ package v1beta1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -267,6 +268,140 @@ type LokiComponentSpec struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// Resources defines the compute resource requests and limits of the component.
+	// They are merged over the defaults of the LokiStack size per resource name.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:resourceRequirements",displayName="Resource Requirements"
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PVCSize defines the size of the persistent volume claims of the component,
+	// overriding the default of the LokiStack size. Only used by the compactor,
+	// ingester, querier, ruler and index gateway. Volume claims cannot shrink.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="PVC Size"
+	PVCSize *resource.Quantity `json:"pvcSize,omitempty"`
+
+	// Affinity defines the scheduling constraints of the component pods.
+	// The pod anti-affinity for zone awareness is added to it.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Affinity"
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// PriorityClassName defines the priority class of the component pods.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:io.kubernetes:PriorityClass",displayName="Priority Class Name"
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// PodSecurityContext defines the security context of the component pods.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Security Context"
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+
+	// SecurityContext defines the security context of the containers of the component.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Security Context"
+	SecurityContext *corev1.SecurityContext `json:"securityContext,omitempty"`
+
+	// PodLabels defines additional labels of the component pods.
+	// Labels set by the operator take precedence.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Labels"
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+
+	// PodAnnotations defines additional annotations of the component pods.
+	// Annotations set by the operator take precedence.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Pod Annotations"
+	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
+
+	// ImagePullSecrets defines the secrets used to pull the component images.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Image Pull Secrets"
+	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+
+	// Autoscaling defines the horizontal pod autoscaling of the component.
+	// Only supported for the distributor, querier, query frontend and gateway.
+	// If defined, Replicas is ignored.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Autoscaling"
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+}
+
+// AutoscalingSpec defines the horizontal pod autoscaling of a component.
+type AutoscalingSpec struct {
+	// MinReplicas defines the lower limit of replica pods of the component.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Min Replicas"
+	MinReplicas int32 `json:"minReplicas"`
+
+	// MaxReplicas defines the upper limit of replica pods of the component.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Max Replicas"
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// TargetCPUUtilization defines the target average CPU utilization of
+	// the replica pods in percent of the requested CPU.
+	// Defaults to 80 if no custom metric is defined.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Target CPU Utilization"
+	TargetCPUUtilization *int32 `json:"targetCPUUtilization,omitempty"`
+
+	// CustomMetric defines a per pod metric to scale the component on,
+	// e.g. the queue length of the query frontend.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Custom Metric"
+	CustomMetric *AutoscalingMetricSpec `json:"customMetric,omitempty"`
+}
+
+// AutoscalingMetricSpec defines a per pod metric served by the
+// custom metrics API of the cluster.
+type AutoscalingMetricSpec struct {
+	// Name of the metric.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Name"
+	Name string `json:"name"`
+
+	// TargetAverageValue defines the target value of the metric averaged
+	// across the replica pods.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Target Average Value"
+	TargetAverageValue resource.Quantity `json:"targetAverageValue"`
 }
 
 // LokiTemplateSpec defines the template of all requirements to configure
@@ -308,6 +443,22 @@ type LokiTemplateSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Query Frontend pods"
 	QueryFrontend *LokiComponentSpec `json:"queryFrontend,omitempty"`
 
+	// Ruler defines the ruler component spec.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Ruler pods"
+	Ruler *LokiComponentSpec `json:"ruler,omitempty"`
+
+	// IndexGateway defines the index gateway component spec.
+	// The index gateway is only deployed if defined. Queriers then
+	// query the index from the gateway instead of downloading it.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Index Gateway pods"
+	IndexGateway *LokiComponentSpec `json:"indexGateway,omitempty"`
+
 	// Gateway defines the lokistack-gateway component spec.
 	//
 	// +optional
@@ -316,8 +467,36 @@ type LokiTemplateSpec struct {
 	Gateway *LokiComponentSpec `json:"gateway,omitempty"`
 }
 
+// ObjectStorageSecretType defines the type of storage which can be used with the Loki cluster.
+//
+// +kubebuilder:validation:Enum=azure;gcs;s3;swift
+type ObjectStorageSecretType string
+
+const (
+	// ObjectStorageSecretAzure when using Azure Blob Storage for Loki storage
+	ObjectStorageSecretAzure ObjectStorageSecretType = "azure"
+
+	// ObjectStorageSecretGCS when using Google Cloud Storage for Loki storage
+	ObjectStorageSecretGCS ObjectStorageSecretType = "gcs"
+
+	// ObjectStorageSecretS3 when using S3 or an S3-compatible endpoint for Loki storage
+	ObjectStorageSecretS3 ObjectStorageSecretType = "s3"
+
+	// ObjectStorageSecretSwift when using OpenStack Swift for Loki storage
+	ObjectStorageSecretSwift ObjectStorageSecretType = "swift"
+)
+
 // ObjectStorageSecretSpec is a secret reference containing name only, no namespace.
 type ObjectStorageSecretSpec struct {
+	// Type of object storage that should be used.
+	// The secret is validated according to the keys required by this type.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:default:=s3
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:azure","urn:alm:descriptor:com.tectonic.ui:select:gcs","urn:alm:descriptor:com.tectonic.ui:select:s3","urn:alm:descriptor:com.tectonic.ui:select:swift"},displayName="Object Storage Secret Type"
+	Type ObjectStorageSecretType `json:"type"`
+
 	// Name of a secret in the namespace configured for object storage secrets.
 	//
 	// +required
@@ -326,6 +505,156 @@ type ObjectStorageSecretSpec struct {
 	Name string `json:"name"`
 }
 
+// ObjectStorageSchemaVersion defines the storage schema version which will be
+// used with the Loki cluster.
+//
+// +kubebuilder:validation:Enum=v11;v12
+type ObjectStorageSchemaVersion string
+
+const (
+	// ObjectStorageSchemaV11 when using v11 for the storage schema
+	ObjectStorageSchemaV11 ObjectStorageSchemaVersion = "v11"
+
+	// ObjectStorageSchemaV12 when using v12 for the storage schema
+	ObjectStorageSchemaV12 ObjectStorageSchemaVersion = "v12"
+)
+
+// ObjectStorageIndexStoreType defines the type of index store which can be used with the Loki cluster.
+//
+// +kubebuilder:validation:Enum=boltdb-shipper
+type ObjectStorageIndexStoreType string
+
+const (
+	// ObjectStorageIndexStoreBoltDBShipper when using boltdb-shipper to store the index in object storage
+	ObjectStorageIndexStoreBoltDBShipper ObjectStorageIndexStoreType = "boltdb-shipper"
+)
+
+// StorageSchemaEffectiveDate defines the type for the Storage Schema Effective Date
+//
+// +kubebuilder:validation:Pattern:="^([0-9]{4,})([-]([0-9]{2})){2}$"
+type StorageSchemaEffectiveDate string
+
+// ObjectStorageSchema defines a period of the storage schema config.
+type ObjectStorageSchema struct {
+	// Version for writing and reading logs.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:v11","urn:alm:descriptor:com.tectonic.ui:select:v12"},displayName="Version"
+	Version ObjectStorageSchemaVersion `json:"version"`
+
+	// EffectiveDate is the date in UTC (YYYY-MM-DD) from which on the schema
+	// period applies. New periods must start after the current date in UTC.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Effective Date"
+	EffectiveDate StorageSchemaEffectiveDate `json:"effectiveDate"`
+
+	// IndexStore is the type of store used to persist the log index.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default:=boltdb-shipper
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:boltdb-shipper"},displayName="Index Store"
+	IndexStore ObjectStorageIndexStoreType `json:"indexStore,omitempty"`
+
+	// ObjectStore is the type of object storage used to persist the log chunks.
+	// Defaults to the type of the object storage secret.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:azure","urn:alm:descriptor:com.tectonic.ui:select:gcs","urn:alm:descriptor:com.tectonic.ui:select:s3","urn:alm:descriptor:com.tectonic.ui:select:swift"},displayName="Object Store"
+	ObjectStore ObjectStorageSecretType `json:"objectStore,omitempty"`
+}
+
+// ObjectStorageTLSSpec is the TLS configuration for reaching the object storage endpoint.
+type ObjectStorageTLSSpec struct {
+	// CA is the name of a ConfigMap containing a CA certificate.
+	// It needs to be in the same namespace as the LokiStack custom resource.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:io.kubernetes:ConfigMap",displayName="CA ConfigMap Name"
+	CA string `json:"caName,omitempty"`
+
+	// CAKey is the data key of the CA ConfigMap containing the CA certificate.
+	// Defaults to "service-ca.crt".
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="CA ConfigMap Key"
+	CAKey string `json:"caKey,omitempty"`
+
+	// InsecureSkipVerify disables the verification of the object storage
+	// endpoint's certificate chain and host name.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch",displayName="Insecure Skip Verify"
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// ObjectStorageSTSSpec is the configuration for authenticating to AWS S3 by
+// assuming an IAM role with a projected service account token (AWS STS).
+type ObjectStorageSTSSpec struct {
+	// RoleARN is the ARN of the IAM role assumed by the Loki components.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^arn:aws[a-z-]*:iam::[0-9]{12}:role/.+$"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="IAM Role ARN"
+	RoleARN string `json:"roleARN"`
+
+	// Audience of the projected service account token.
+	// Defaults to "sts.amazonaws.com".
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Token Audience"
+	Audience string `json:"audience,omitempty"`
+}
+
+// ObjectStorageSSEType defines the type of server-side encryption.
+//
+// +kubebuilder:validation:Enum:="SSE-S3";"SSE-KMS"
+type ObjectStorageSSEType string
+
+const (
+	// ObjectStorageSSES3 encrypts objects with keys managed by S3.
+	ObjectStorageSSES3 ObjectStorageSSEType = "SSE-S3"
+
+	// ObjectStorageSSEKMS encrypts objects with keys managed by AWS KMS.
+	ObjectStorageSSEKMS ObjectStorageSSEType = "SSE-KMS"
+)
+
+// ObjectStorageSSESpec is the server-side encryption configuration for the object storage.
+// Currently only supported for the object storage secret type s3.
+type ObjectStorageSSESpec struct {
+	// Type of server-side encryption.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:SSE-S3","urn:alm:descriptor:com.tectonic.ui:select:SSE-KMS"},displayName="Encryption Type"
+	Type ObjectStorageSSEType `json:"type"`
+
+	// KMSKeyID is the ID of the AWS KMS key used to encrypt objects.
+	// Only allowed with type SSE-KMS. Defaults to the AWS managed key.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KMS Key ID"
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+
+	// KMSEncryptionContext is the AWS KMS encryption context used with the KMS key.
+	// Only allowed with type SSE-KMS.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="KMS Encryption Context"
+	KMSEncryptionContext map[string]string `json:"kmsEncryptionContext,omitempty"`
+}
+
 // ObjectStorageSpec defines the requirements to access the object
 // storage bucket to persist logs by the ingester component.
 type ObjectStorageSpec struct {
@@ -335,6 +664,39 @@ type ObjectStorageSpec struct {
 	// +required
 	// +kubebuilder:validation:Required
 	Secret ObjectStorageSecretSpec `json:"secret"`
+
+	// Schemas for reading and writing logs, ordered by effective date.
+	// Existing periods must not be changed and new periods must start in the future.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinItems:=1
+	// +kubebuilder:default:={{version:v11,effectiveDate:"2020-10-01"}}
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Object Storage Schemas"
+	Schemas []ObjectStorageSchema `json:"schemas,omitempty"`
+
+	// TLS configuration for reaching the object storage endpoint.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="TLS Config"
+	TLS *ObjectStorageTLSSpec `json:"tls,omitempty"`
+
+	// STS configures short-lived credentials by assuming an IAM role with a
+	// projected service account token instead of using static access keys.
+	// Only supported for the object storage secret type s3.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="STS Config"
+	STS *ObjectStorageSTSSpec `json:"sts,omitempty"`
+
+	// SSE configures the server-side encryption of the stored objects.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Server-Side Encryption"
+	SSE *ObjectStorageSSESpec `json:"sse,omitempty"`
 }
 
 // QueryLimitSpec defines the limits applies at the query path.
@@ -424,6 +786,50 @@ type IngestionLimitSpec struct {
 	MaxLineSize int32 `json:"maxLineSize,omitempty"`
 }
 
+// RetentionStreamSpec defines a log stream with a separate retention period.
+type RetentionStreamSpec struct {
+	// Days contains the number of days logs of the stream are kept.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Days"
+	Days uint `json:"days"`
+
+	// Priority defines which rule applies if a stream matches multiple selectors.
+	// The rule with the highest priority is used.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Priority"
+	Priority uint32 `json:"priority,omitempty"`
+
+	// Selector is a LogQL stream selector matching the log streams, e.g. {namespace="default"}.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selector"
+	Selector string `json:"selector"`
+}
+
+// RetentionLimitSpec defines how long logs are kept in the object storage.
+type RetentionLimitSpec struct {
+	// Days contains the number of days logs are kept.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Days"
+	Days uint `json:"days"`
+
+	// Streams defines log streams with a separate retention period.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Streams"
+	Streams []RetentionStreamSpec `json:"streams,omitempty"`
+}
+
 // LimitsTemplateSpec defines the limits  applied at ingestion or query path.
 type LimitsTemplateSpec struct {
 	// IngestionLimits defines the limits applied on ingested log streams.
@@ -437,6 +843,13 @@ type LimitsTemplateSpec struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	QueryLimits *QueryLimitSpec `json:"queries,omitempty"`
+
+	// Retention defines how long logs are kept in the object storage.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Retention"
+	Retention *RetentionLimitSpec `json:"retention,omitempty"`
 }
 
 // LimitsSpec defines the spec for limits applied at ingestion or query
@@ -458,6 +871,158 @@ type LimitsSpec struct {
 	Tenants map[string]LimitsTemplateSpec `json:"tenants,omitempty"`
 }
 
+// RemoteWriteSpec defines the endpoint the ruler writes
+// the results of recording rules to.
+type RemoteWriteSpec struct {
+	// URL of the Prometheus remote-write compatible endpoint.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern:="^https?://.+"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="URL"
+	URL string `json:"url"`
+}
+
+// RulesSpec defines the spec for the ruler component.
+type RulesSpec struct {
+	// Enabled defines a flag to enable/disable the ruler component
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch",displayName="Enable"
+	Enabled bool `json:"enabled"`
+
+	// Selector defines the labels of the AlertingRule and RecordingRule
+	// resources in the LokiStack namespace to load rules from.
+	// Defaults to all rule resources in the namespace.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Selector"
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// AlertManagerEndpoints defines the list of Alertmanager URLs
+	// alerts are sent to.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Alertmanager Endpoints"
+	AlertManagerEndpoints []string `json:"alertManagerEndpoints,omitempty"`
+
+	// RemoteWrite defines the endpoint the results of recording rules are written to.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Remote Write"
+	RemoteWrite *RemoteWriteSpec `json:"remoteWrite,omitempty"`
+}
+
+// CacheType defines the type of a cache backend.
+//
+// +kubebuilder:validation:Enum=memcached;redis
+type CacheType string
+
+const (
+	// CacheMemcached when using memcached as cache backend
+	CacheMemcached CacheType = "memcached"
+
+	// CacheRedis when using Redis as cache backend
+	CacheRedis CacheType = "redis"
+)
+
+// CacheSpec defines a cache backend used by the Loki components.
+type CacheSpec struct {
+	// Type of the cache backend.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors={"urn:alm:descriptor:com.tectonic.ui:select:memcached","urn:alm:descriptor:com.tectonic.ui:select:redis"},displayName="Type"
+	Type CacheType `json:"type"`
+
+	// Managed deploys a memcached statefulset for the cache sized by
+	// the LokiStack size. Only supported for memcached.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch",displayName="Managed"
+	Managed bool `json:"managed,omitempty"`
+
+	// Endpoints defines the host:port addresses of an external cache.
+	// Required if the cache is not managed.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Endpoints"
+	Endpoints []string `json:"endpoints,omitempty"`
+}
+
+// CachesSpec defines the caches used by the Loki components.
+type CachesSpec struct {
+	// Chunks defines the cache for chunks fetched from the object storage.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Chunks Cache"
+	Chunks *CacheSpec `json:"chunks,omitempty"`
+
+	// IndexQueries defines the cache for index query results.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Index Queries Cache"
+	IndexQueries *CacheSpec `json:"indexQueries,omitempty"`
+
+	// Results defines the cache for query results of the query frontend.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Results Cache"
+	Results *CacheSpec `json:"results,omitempty"`
+}
+
+// ZoneAwarenessSpec defines the topology domains the component pods are spread across.
+type ZoneAwarenessSpec struct {
+	// TopologyKeys defines the node labels forming a zone, e.g. topology.kubernetes.io/zone.
+	// The pods of each component are spread across the zones and ingesters replicate
+	// log streams to ingesters in different zones.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems:=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Topology Keys"
+	TopologyKeys []string `json:"topologyKeys"`
+}
+
+// WriteAheadLogSpec defines the write-ahead log of the ingesters.
+type WriteAheadLogSpec struct {
+	// Enabled writes incoming log lines to the write-ahead log before
+	// acknowledging them. Restarted ingesters replay the log to recover
+	// chunks that were not flushed yet.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch",displayName="Enabled"
+	Enabled bool `json:"enabled"`
+
+	// ReplayMemoryCeiling is the memory threshold at which a replaying ingester
+	// flushes its chunks before continuing the replay. Defaults to 75% of the
+	// ingester memory request.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Replay Memory Ceiling"
+	ReplayMemoryCeiling *resource.Quantity `json:"replayMemoryCeiling,omitempty"`
+
+	// CheckpointDuration is the interval between write-ahead log checkpoints,
+	// e.g. 5m. Defaults to 5m.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern:="^([0-9]+(\\.[0-9]+)?(ms|s|m|h))+$"
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,displayName="Checkpoint Duration"
+	CheckpointDuration string `json:"checkpointDuration,omitempty"`
+}
+
 // LokiStackSpec defines the desired state of LokiStack
 type LokiStackSpec struct {
 
@@ -499,6 +1064,22 @@ type LokiStackSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:number",displayName="Replication Factor"
 	ReplicationFactor int32 `json:"replicationFactor"`
 
+	// ZoneAwareness defines the spread of the component pods and
+	// the log stream replicas across availability zones.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced",displayName="Zone Awareness"
+	ZoneAwareness *ZoneAwarenessSpec `json:"zoneAwareness,omitempty"`
+
+	// WriteAheadLog defines the write-ahead log of the ingesters. The log is
+	// stored on the ingester persistent volume.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced",displayName="Write-Ahead Log"
+	WriteAheadLog *WriteAheadLogSpec `json:"writeAheadLog,omitempty"`
+
 	// Limits defines the limits to be applied to log stream processing.
 	//
 	// +optional
@@ -506,6 +1087,21 @@ type LokiStackSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced",displayName="Rate Limiting"
 	Limits *LimitsSpec `json:"limits,omitempty"`
 
+	// Rules defines the spec for the ruler component evaluating
+	// AlertingRule and RecordingRule resources.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced",displayName="Rules"
+	Rules *RulesSpec `json:"rules,omitempty"`
+
+	// Caches defines the memcached or Redis caches used by the Loki components.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec,xDescriptors="urn:alm:descriptor:com.tectonic.ui:advanced",displayName="Caches"
+	Caches *CachesSpec `json:"caches,omitempty"`
+
 	// Template defines the resource/limits/tolerations/nodeselectors per component
 	//
 	// +optional
@@ -549,11 +1145,57 @@ const (
 	ReasonPendingComponents LokiStackConditionReason = "PendingComponents"
 	// ReasonReadyComponents when all LokiStack components are ready to serve traffic.
 	ReasonReadyComponents LokiStackConditionReason = "ReadyComponents"
+	// ReasonCreatedComponents when LokiStack component resources were created.
+	// Used as event reason only.
+	ReasonCreatedComponents LokiStackConditionReason = "CreatedComponents"
+	// ReasonUpdatedComponents when LokiStack component resources were updated.
+	// Used as event reason only.
+	ReasonUpdatedComponents LokiStackConditionReason = "UpdatedComponents"
+	// ReasonDeletedComponents when LokiStack component resources no longer required were deleted.
+	// Used as event reason only.
+	ReasonDeletedComponents LokiStackConditionReason = "DeletedComponents"
 	// ReasonMissingObjectStorageSecret when the required secret to store logs to object
 	// storage is missing.
 	ReasonMissingObjectStorageSecret LokiStackConditionReason = "MissingObjectStorageSecret"
 	// ReasonInvalidObjectStorageSecret when the format of the secret is invalid.
 	ReasonInvalidObjectStorageSecret LokiStackConditionReason = "InvalidObjectStorageSecret"
+	// ReasonMissingObjectStorageCAConfigMap when the required configmap to verify object storage
+	// certificates is missing.
+	ReasonMissingObjectStorageCAConfigMap LokiStackConditionReason = "MissingObjectStorageCAConfigMap"
+	// ReasonInvalidObjectStorageCAConfigMap when the object storage CA configmap does not
+	// contain the configured CA key.
+	ReasonInvalidObjectStorageCAConfigMap LokiStackConditionReason = "InvalidObjectStorageCAConfigMap"
+	// ReasonInvalidObjectStorageSchema when the spec contains an invalid schema(s).
+	ReasonInvalidObjectStorageSchema LokiStackConditionReason = "InvalidObjectStorageSchema"
+	// ReasonInvalidObjectStorageEncryption when the server-side encryption configuration is invalid.
+	ReasonInvalidObjectStorageEncryption LokiStackConditionReason = "InvalidObjectStorageEncryption"
+	// ReasonObjectStorageUnreachable when the object storage endpoint or bucket cannot be reached.
+	ReasonObjectStorageUnreachable LokiStackConditionReason = "ObjectStorageUnreachable"
+	// ReasonInvalidRulesConfiguration when the selected alerting or recording rules are invalid.
+	ReasonInvalidRulesConfiguration LokiStackConditionReason = "InvalidRulesConfiguration"
+	// ReasonInvalidCacheConfiguration when a cache is neither managed nor has endpoints,
+	// or a managed cache is not of type memcached.
+	ReasonInvalidCacheConfiguration LokiStackConditionReason = "InvalidCacheConfiguration"
+	// ReasonInvalidAutoscalingConfiguration when autoscaling is defined for a component
+	// not supporting it or the min replicas exceed the max replicas.
+	ReasonInvalidAutoscalingConfiguration LokiStackConditionReason = "InvalidAutoscalingConfiguration"
+	// ReasonPVCShrinkNotSupported when the persistent volume claims of an existing
+	// statefulset would shrink.
+	ReasonPVCShrinkNotSupported LokiStackConditionReason = "PVCShrinkNotSupported"
+	// ReasonVolumeExpansionNotSupported when the persistent volume claims of an existing
+	// statefulset should grow but the storage class does not allow volume expansion.
+	ReasonVolumeExpansionNotSupported LokiStackConditionReason = "VolumeExpansionNotSupported"
+	// ReasonVolumeExpansionFailed when the persistent volume claims of an existing
+	// statefulset cannot be expanded.
+	ReasonVolumeExpansionFailed LokiStackConditionReason = "VolumeExpansionFailed"
+	// ReasonUnhealthyRingMembers when ingesters are pending or unhealthy in the ring
+	// while their pods are running.
+	ReasonUnhealthyRingMembers LokiStackConditionReason = "UnhealthyRingMembers"
+	// ReasonRingUnreachable when the ingester ring cannot be read from the distributors.
+	ReasonRingUnreachable LokiStackConditionReason = "RingUnreachable"
+	// ReasonMissingNodeTopologyKey when the node of an ingester pod lacks a topology key
+	// of the zone awareness configuration.
+	ReasonMissingNodeTopologyKey LokiStackConditionReason = "MissingNodeTopologyKey"
 	// ReasonInvalidReplicationConfiguration when the configurated replication factor is not valid
 	// with the select cluster size.
 	ReasonInvalidReplicationConfiguration LokiStackConditionReason = "InvalidReplicationConfiguration"
@@ -615,6 +1257,192 @@ type LokiStackComponentStatus struct {
 	// +kubebuilder:validation:Optional
 	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:com.tectonic.ui:podStatuses",displayName="Gateway",order=5
 	Gateway PodStatusMap `json:"gateway,omitempty"`
+
+	// Ruler is a map to the per pod status of the ruler statefulset.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:com.tectonic.ui:podStatuses",displayName="Ruler",order=6
+	Ruler PodStatusMap `json:"ruler,omitempty"`
+
+	// IndexGateway is a map to the per pod status of the index gateway statefulset.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,xDescriptors="urn:alm:descriptor:com.tectonic.ui:podStatuses",displayName="Index Gateway",order=7
+	IndexGateway PodStatusMap `json:"indexGateway,omitempty"`
+
+	// Replicas lists the ready and desired replicas per component,
+	// summed over the component's deployments and statefulsets.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Replicas",order=8
+	Replicas []ComponentReplicasStatus `json:"replicas,omitempty"`
+}
+
+// ComponentReplicasStatus defines the ready and desired replicas of a LokiStack component.
+type ComponentReplicasStatus struct {
+	// Component is the name of the component, e.g. ingester or lokistack-gateway.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Component string `json:"component"`
+
+	// Ready is the number of ready pods of the component.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Ready int32 `json:"ready"`
+
+	// Desired is the number of pods the component is scaled to.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Desired int32 `json:"desired"`
+}
+
+// LokiStackStorageStatus defines the observed state of
+// the Loki storage configuration.
+type LokiStackStorageStatus struct {
+	// Schemas is a list of schemas which have been applied
+	// to the LokiStack.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Schemas []ObjectStorageSchema `json:"schemas,omitempty"`
+
+	// VolumeExpansions is a list of persistent volume claims
+	// which are being expanded to the requested size.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	VolumeExpansions []VolumeExpansionStatus `json:"volumeExpansions,omitempty"`
+}
+
+// VolumeExpansionStatus defines the observed state of a persistent
+// volume claim being expanded.
+type VolumeExpansionStatus struct {
+	// Name of the persistent volume claim.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Requested is the storage size requested by the persistent volume claim.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Requested resource.Quantity `json:"requested"`
+
+	// Capacity is the actual storage size of the persistent volume.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Capacity resource.Quantity `json:"capacity,omitempty"`
+
+	// Condition is the type of the latest resize condition
+	// of the persistent volume claim, e.g. Resizing or FileSystemResizePending.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Condition string `json:"condition,omitempty"`
+}
+
+// RolloutPhase is the phase of the operator-driven rolling restart.
+//
+// +kubebuilder:validation:Enum=Complete;WaitingForReadyPods;Flushing;Leaving;ShutdownTimedOut
+type RolloutPhase string
+
+const (
+	// RolloutPhaseComplete when all pods run the current revision.
+	RolloutPhaseComplete RolloutPhase = "Complete"
+	// RolloutPhaseWaitingForReadyPods when the rollout waits for all pods
+	// to be ready before restarting the next one.
+	RolloutPhaseWaitingForReadyPods RolloutPhase = "WaitingForReadyPods"
+	// RolloutPhaseFlushing when the pod is flushing its chunks and
+	// still active in the ring.
+	RolloutPhaseFlushing RolloutPhase = "Flushing"
+	// RolloutPhaseLeaving when the pod is leaving the ring.
+	RolloutPhaseLeaving RolloutPhase = "Leaving"
+	// RolloutPhaseShutdownTimedOut when the pod did not leave the ring in time.
+	// The pod is not deleted to avoid losing unflushed data.
+	RolloutPhaseShutdownTimedOut RolloutPhase = "ShutdownTimedOut"
+)
+
+// RolloutStatus defines the observed state of an operator-driven rolling restart.
+type RolloutStatus struct {
+	// Revision is the statefulset revision the pods are rolled to.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Revision string `json:"revision,omitempty"`
+
+	// Replicas is the number of pods of the statefulset.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Replicas int32 `json:"replicas,omitempty"`
+
+	// UpdatedReplicas is the number of pods running the current revision.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	UpdatedReplicas int32 `json:"updatedReplicas,omitempty"`
+
+	// Pod is the name of the pod currently restarted.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Pod string `json:"pod,omitempty"`
+
+	// Phase of the rollout.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Phase RolloutPhase `json:"phase,omitempty"`
+}
+
+// RingMemberStatus defines the observed state of an instance in the ingester ring.
+type RingMemberStatus struct {
+	// Name is the instance ID in the ring, i.e. the ingester pod name.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// State of the instance in the ring, e.g. ACTIVE, LEAVING, PENDING or UNHEALTHY.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	State string `json:"state"`
+
+	// Zone is the availability zone of the instance if zone awareness is enabled.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Zone string `json:"zone,omitempty"`
+
+	// Tokens is the number of tokens registered by the instance.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Tokens int32 `json:"tokens,omitempty"`
+
+	// TokenOwnership is the percentage of the token range owned by the instance.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	TokenOwnership string `json:"tokenOwnership,omitempty"`
+}
+
+// RingStatus defines the observed state of the ingester ring.
+type RingStatus struct {
+	// Ingesters lists all instances of the ingester ring.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Ingesters []RingMemberStatus `json:"ingesters,omitempty"`
 }
 
 // LokiStackStatus defines the observed state of LokiStack
@@ -626,6 +1454,29 @@ type LokiStackStatus struct {
 	// +kubebuilder:validation:Optional
 	Components LokiStackComponentStatus `json:"components,omitempty"`
 
+	// Storage provides summary of all changes that have occurred
+	// to the storage configuration.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Storage Status"
+	Storage LokiStackStorageStatus `json:"storage,omitempty"`
+
+	// IngesterRollout shows the progress of the operator-driven
+	// rolling restart of the ingesters.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Ingester Rollout"
+	IngesterRollout *RolloutStatus `json:"ingesterRollout,omitempty"`
+
+	// Ring shows the state of the ingester ring as seen by the distributors.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Ingester Ring"
+	Ring *RingStatus `json:"ring,omitempty"`
+
 	// Conditions of the Loki deployment health.
 	//
 	// +optional
@@ -11,6 +11,7 @@ import (
 	"github.com/ViaQ/logerr/log"
 	"github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	"sigs.k8s.io/yaml"
 )
 
@@ -21,7 +22,7 @@ type config struct {
 	Image     string
 
 	featureFlags  manifests.FeatureFlags
-	objectStorage manifests.ObjectStorage
+	objectStorage storage.S3StorageConfig
 
 	crFilepath string
 	writeToDir string
@@ -39,12 +40,10 @@ func (c *config) registerFlags(f *flag.FlagSet) {
 	f.BoolVar(&c.featureFlags.EnableTLSServiceMonitorConfig, "with-tls-service-monitors", false, "Enable TLS endpoint for service monitors.")
 	f.BoolVar(&c.featureFlags.EnableGateway, "with-lokistack-gateway", false, "Enables the manifest creation for the entire lokistack-gateway.")
 	// Object storage options
-	c.objectStorage = manifests.ObjectStorage{}
+	c.objectStorage = storage.S3StorageConfig{}
 	f.StringVar(&c.objectStorage.Endpoint, "object-storage.endpoint", "", "The S3 endpoint location.")
 	f.StringVar(&c.objectStorage.Buckets, "object-storage.buckets", "", "A comma-separated list of S3 buckets.")
 	f.StringVar(&c.objectStorage.Region, "object-storage.region", "", "An S3 region.")
-	f.StringVar(&c.objectStorage.AccessKeyID, "object-storage.access-key-id", "", "The access key id for S3.")
-	f.StringVar(&c.objectStorage.AccessKeySecret, "object-storage.access-key-secret", "", "The access key secret for S3.")
 	// Input and output file/dir options
 	f.StringVar(&c.crFilepath, "custom-resource.path", "", "Path to a custom resource YAML file.")
 	f.StringVar(&c.writeToDir, "output.write-dir", "", "write each file to the specified directory.")
@@ -72,14 +71,6 @@ func (c *config) validateFlags() {
 		log.Info("-object.storage.buckets flag is required")
 		os.Exit(1)
 	}
-	if cfg.objectStorage.AccessKeyID == "" {
-		log.Info("-object.storage.access.key.id flag is required")
-		os.Exit(1)
-	}
-	if cfg.objectStorage.AccessKeySecret == "" {
-		log.Info("-object.storage.access.key.secret flag is required")
-		os.Exit(1)
-	}
 }
 
 var cfg *config
@@ -112,12 +103,16 @@ func main() {
 
 	// Convert config to manifest.Options
 	opts := manifests.Options{
-		Name:          cfg.Name,
-		Namespace:     cfg.Namespace,
-		Image:         cfg.Image,
-		Stack:         ls.Spec,
-		Flags:         cfg.featureFlags,
-		ObjectStorage: cfg.objectStorage,
+		Name:      cfg.Name,
+		Namespace: cfg.Namespace,
+		Image:     cfg.Image,
+		Stack:     ls.Spec,
+		Flags:     cfg.featureFlags,
+		ObjectStorage: storage.Options{
+			SecretName:  ls.Spec.Storage.Secret.Name,
+			SharedStore: v1beta1.ObjectStorageSecretS3,
+			S3:          &cfg.objectStorage,
+		},
 	}
 
 	if optErr := manifests.ApplyDefaultSettings(&opts); optErr != nil {
@@ -21,7 +21,9 @@ import (
 	"time"
 
 	"github.com/ViaQ/loki-operator/controllers/internal/management/state"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
 	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage"
 	"github.com/ViaQ/loki-operator/internal/handlers"
 	"github.com/ViaQ/loki-operator/internal/manifests"
 	"github.com/ViaQ/loki-operator/internal/status"
@@ -29,20 +31,37 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 )
 
+const (
+	// ingesterRolloutInterval is the interval for checking the progress of an ingester rollout.
+	ingesterRolloutInterval = 10 * time.Second
+
+	// ringInspectionInterval is the interval for checking the ingester ring while members are unhealthy.
+	ringInspectionInterval = 30 * time.Second
+)
+
 var (
 	createOrUpdateOnlyPred = builder.WithPredicates(predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
@@ -81,15 +100,27 @@ type LokiStackReconciler struct {
 	client.Client
 	Log    logr.Logger
 	Scheme *runtime.Scheme
-	Flags  manifests.FeatureFlags
+	Prober objectstorage.Prober
+	// Recorder records reconcile outcomes as events on the LokiStack.
+	Recorder record.EventRecorder
+	// IngesterClient flushes and shuts down ingesters during rollouts
+	// and inspects the ingester ring.
+	IngesterClient ingester.Client
+	Flags          manifests.FeatureFlags
 }
 
 // +kubebuilder:rbac:groups=loki.openshift.io,resources=lokistacks,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=loki.openshift.io,resources=lokistacks/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=loki.openshift.io,resources=lokistacks/finalizers,verbs=update
+// +kubebuilder:rbac:groups=loki.openshift.io,resources=alertingrules;recordingrules,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=pods;nodes;services;endpoints;configmaps;serviceaccounts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings;clusterroles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors,verbs=get;list;watch;create;update
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
@@ -119,7 +150,25 @@ func (r *LokiStackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, nil
 	}
 
-	err = handlers.CreateOrUpdateLokiStack(ctx, req, r.Client, r.Scheme, r.Flags)
+	err = handlers.CreateOrUpdateLokiStack(ctx, req, r.Client, r.Recorder, r.Scheme, r.Prober, r.Flags)
+	if err != nil {
+		return ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: time.Second,
+		}, err
+	}
+
+	// Inspect the ring first, so that the status refresh
+	// does not reset the Degraded condition of an unhealthy ring.
+	unhealthy, err := handlers.InspectRing(ctx, req, r.Client, r.Recorder, r.IngesterClient, r.Flags)
+	if err != nil {
+		return ctrl.Result{
+			Requeue:      true,
+			RequeueAfter: time.Second,
+		}, err
+	}
+
+	err = status.Refresh(ctx, r.Client, req, unhealthy)
 	if err != nil {
 		return ctrl.Result{
 			Requeue:      true,
@@ -127,13 +176,25 @@ func (r *LokiStackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		}, err
 	}
 
-	err = status.Refresh(ctx, r.Client, req)
+	inProgress, err := handlers.RolloutIngesters(ctx, req, r.Client, r.IngesterClient, r.Flags)
 	if err != nil {
 		return ctrl.Result{
 			Requeue:      true,
 			RequeueAfter: time.Second,
 		}, err
 	}
+	if inProgress {
+		// Poll the ring until the ingester pods are replaced.
+		return ctrl.Result{
+			RequeueAfter: ingesterRolloutInterval,
+		}, nil
+	}
+	if unhealthy {
+		// Ring states change without any Kubernetes event.
+		return ctrl.Result{
+			RequeueAfter: ringInspectionInterval,
+		}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
@@ -153,7 +214,12 @@ func (r *LokiStackReconciler) buildController(bld k8s.Builder) error {
 		Owns(&appsv1.Deployment{}, updateOrDeleteOnlyPred).
 		Owns(&appsv1.StatefulSet{}, updateOrDeleteOnlyPred).
 		Owns(&rbacv1.ClusterRole{}, updateOrDeleteOnlyPred).
-		Owns(&rbacv1.ClusterRoleBinding{}, updateOrDeleteOnlyPred)
+		Owns(&rbacv1.ClusterRoleBinding{}, updateOrDeleteOnlyPred).
+		Owns(&autoscalingv2beta2.HorizontalPodAutoscaler{}, updateOrDeleteOnlyPred).
+		Owns(&policyv1.PodDisruptionBudget{}, updateOrDeleteOnlyPred).
+		Watches(&source.Kind{Type: &corev1.Secret{}}, r.enqueueForStorageSecret()).
+		Watches(&source.Kind{Type: &lokiv1beta1.AlertingRule{}}, r.enqueueForRules()).
+		Watches(&source.Kind{Type: &lokiv1beta1.RecordingRule{}}, r.enqueueForRules())
 
 	if r.Flags.EnableGatewayRoute {
 		bld = bld.Owns(&routev1.Route{}, updateOrDeleteOnlyPred)
@@ -163,3 +229,76 @@ func (r *LokiStackReconciler) buildController(bld k8s.Builder) error {
 
 	return bld.Complete(r)
 }
+
+// enqueueForStorageSecret maps events of object storage secrets to
+// reconcile requests for all LokiStacks referencing them.
+func (r *LokiStackReconciler) enqueueForStorageSecret() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.mapStorageSecret)
+}
+
+func (r *LokiStackReconciler) mapStorageSecret(obj client.Object) []reconcile.Request {
+	stacks := &lokiv1beta1.LokiStackList{}
+	if err := r.Client.List(context.TODO(), stacks, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list lokistacks for object storage secret event", "name", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, stack := range stacks.Items {
+		if stack.Spec.Storage.Secret.Name != obj.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      stack.Name,
+				Namespace: stack.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
+
+// enqueueForRules maps events of alerting and recording rules to
+// reconcile requests for all LokiStacks selecting them.
+func (r *LokiStackReconciler) enqueueForRules() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.mapRules)
+}
+
+func (r *LokiStackReconciler) mapRules(obj client.Object) []reconcile.Request {
+	stacks := &lokiv1beta1.LokiStackList{}
+	if err := r.Client.List(context.TODO(), stacks, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list lokistacks for rules event", "name", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, stack := range stacks.Items {
+		rules := stack.Spec.Rules
+		if rules == nil || !rules.Enabled {
+			continue
+		}
+
+		if rules.Selector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(rules.Selector)
+			if err != nil {
+				r.Log.Error(err, "failed to parse rules selector", "name", stack.Name, "namespace", stack.Namespace)
+				continue
+			}
+
+			if !selector.Matches(labels.Set(obj.GetLabels())) {
+				continue
+			}
+		}
+
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      stack.Name,
+				Namespace: stack.Namespace,
+			},
+		})
+	}
+
+	return requests
+}
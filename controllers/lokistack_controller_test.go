@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"context"
 	"flag"
 	"io/ioutil"
 	"os"
@@ -10,18 +11,25 @@ import (
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/go-logr/logr"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var scheme = runtime.NewScheme()
@@ -57,6 +65,7 @@ func TestLokiStackController_RegistersCustomResourceForCreateOrUpdate(t *testing
 
 	b.ForReturns(b)
 	b.OwnsReturns(b)
+	b.WatchesReturns(b)
 
 	err := c.buildController(b)
 	require.NoError(t, err)
@@ -117,8 +126,18 @@ func TestLokiStackController_RegisterOwnedResourcesForUpdateOrDeleteOnly(t *test
 			pred:  updateOrDeleteOnlyPred,
 		},
 		{
-			obj:   &networkingv1.Ingress{},
+			obj:   &autoscalingv2beta2.HorizontalPodAutoscaler{},
 			index: 7,
+			pred:  updateOrDeleteOnlyPred,
+		},
+		{
+			obj:   &policyv1.PodDisruptionBudget{},
+			index: 8,
+			pred:  updateOrDeleteOnlyPred,
+		},
+		{
+			obj:   &networkingv1.Ingress{},
+			index: 9,
 			flags: manifests.FeatureFlags{
 				EnableGatewayRoute: false,
 			},
@@ -126,7 +145,7 @@ func TestLokiStackController_RegisterOwnedResourcesForUpdateOrDeleteOnly(t *test
 		},
 		{
 			obj:   &routev1.Route{},
-			index: 7,
+			index: 9,
 			flags: manifests.FeatureFlags{
 				EnableGatewayRoute: true,
 			},
@@ -137,13 +156,14 @@ func TestLokiStackController_RegisterOwnedResourcesForUpdateOrDeleteOnly(t *test
 		b := &k8sfakes.FakeBuilder{}
 		b.ForReturns(b)
 		b.OwnsReturns(b)
+		b.WatchesReturns(b)
 
 		c := &LokiStackReconciler{Client: k, Scheme: scheme, Flags: tst.flags}
 		err := c.buildController(b)
 		require.NoError(t, err)
 
 		// Require Owns-Calls for all owned resources
-		require.Equal(t, 8, b.OwnsCallCount())
+		require.Equal(t, 10, b.OwnsCallCount())
 
 		// Require Owns-call options to have delete predicate only
 		obj, opts := b.OwnsArgsForCall(tst.index)
@@ -151,3 +171,179 @@ func TestLokiStackController_RegisterOwnedResourcesForUpdateOrDeleteOnly(t *test
 		require.Equal(t, tst.pred, opts[0])
 	}
 }
+
+func TestLokiStackController_WatchesObjectStorageSecrets(t *testing.T) {
+	b := &k8sfakes.FakeBuilder{}
+	k := &k8sfakes.FakeClient{}
+	c := &LokiStackReconciler{Client: k, Scheme: scheme}
+
+	b.ForReturns(b)
+	b.OwnsReturns(b)
+	b.WatchesReturns(b)
+
+	err := c.buildController(b)
+	require.NoError(t, err)
+
+	// Require Watches-Calls for object storage secrets and rules
+	require.Equal(t, 3, b.WatchesCallCount())
+
+	src, _, _ := b.WatchesArgsForCall(0)
+	require.Equal(t, &source.Kind{Type: &corev1.Secret{}}, src)
+}
+
+func TestLokiStackController_WatchesRules(t *testing.T) {
+	b := &k8sfakes.FakeBuilder{}
+	k := &k8sfakes.FakeClient{}
+	c := &LokiStackReconciler{Client: k, Scheme: scheme}
+
+	b.ForReturns(b)
+	b.OwnsReturns(b)
+	b.WatchesReturns(b)
+
+	err := c.buildController(b)
+	require.NoError(t, err)
+
+	src, _, _ := b.WatchesArgsForCall(1)
+	require.Equal(t, &source.Kind{Type: &lokiv1beta1.AlertingRule{}}, src)
+
+	src, _, _ = b.WatchesArgsForCall(2)
+	require.Equal(t, &source.Kind{Type: &lokiv1beta1.RecordingRule{}}, src)
+}
+
+func TestLokiStackController_MapStorageSecretToReferencingStacks(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+	c := &LokiStackReconciler{Client: k, Scheme: scheme, Log: logr.Discard()}
+
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		l := list.(*lokiv1beta1.LokiStackList)
+		l.Items = []lokiv1beta1.LokiStack{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-with-secret",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.LokiStackSpec{
+					Storage: lokiv1beta1.ObjectStorageSpec{
+						Secret: lokiv1beta1.ObjectStorageSecretSpec{
+							Name: "storage-secret",
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-with-other-secret",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.LokiStackSpec{
+					Storage: lokiv1beta1.ObjectStorageSpec{
+						Secret: lokiv1beta1.ObjectStorageSecretSpec{
+							Name: "other-secret",
+						},
+					},
+				},
+			},
+		}
+		return nil
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "storage-secret",
+			Namespace: "some-ns",
+		},
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      "stack-with-secret",
+				Namespace: "some-ns",
+			},
+		},
+	}
+
+	require.Equal(t, want, c.mapStorageSecret(secret))
+}
+
+func TestLokiStackController_MapRulesToSelectingStacks(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+	c := &LokiStackReconciler{Client: k, Scheme: scheme, Log: logr.Discard()}
+
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		l := list.(*lokiv1beta1.LokiStackList)
+		l.Items = []lokiv1beta1.LokiStack{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-without-rules",
+					Namespace: "some-ns",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-with-all-rules",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.LokiStackSpec{
+					Rules: &lokiv1beta1.RulesSpec{
+						Enabled: true,
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-with-matching-rules",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.LokiStackSpec{
+					Rules: &lokiv1beta1.RulesSpec{
+						Enabled: true,
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"team": "a"},
+						},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "stack-with-other-rules",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.LokiStackSpec{
+					Rules: &lokiv1beta1.RulesSpec{
+						Enabled: true,
+						Selector: &metav1.LabelSelector{
+							MatchLabels: map[string]string{"team": "b"},
+						},
+					},
+				},
+			},
+		}
+		return nil
+	}
+
+	rule := &lokiv1beta1.AlertingRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "alerts",
+			Namespace: "some-ns",
+			Labels:    map[string]string{"team": "a"},
+		},
+	}
+
+	want := []reconcile.Request{
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      "stack-with-all-rules",
+				Namespace: "some-ns",
+			},
+		},
+		{
+			NamespacedName: types.NamespacedName{
+				Name:      "stack-with-matching-rules",
+				Namespace: "some-ns",
+			},
+		},
+	}
+
+	require.Equal(t, want, c.mapRules(rule))
+}
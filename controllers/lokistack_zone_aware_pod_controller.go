@@ -0,0 +1,96 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/handlers"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// unannotatedIngesterPodPred filters for scheduled ingester pods
+// missing the availability zone annotation.
+var unannotatedIngesterPodPred = builder.WithPredicates(predicate.Funcs{
+	UpdateFunc:  func(e event.UpdateEvent) bool { return isUnannotatedIngesterPod(e.ObjectNew) },
+	CreateFunc:  func(e event.CreateEvent) bool { return isUnannotatedIngesterPod(e.Object) },
+	DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+	GenericFunc: func(e event.GenericEvent) bool { return false },
+})
+
+func isUnannotatedIngesterPod(obj client.Object) bool {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return false
+	}
+
+	if pod.Labels[manifests.LabelComponent] != manifests.LabelIngesterComponent {
+		return false
+	}
+
+	if _, ok := pod.Annotations[manifests.AnnotationAvailabilityZone]; ok {
+		return false
+	}
+
+	return pod.Spec.NodeName != ""
+}
+
+// LokiStackZoneAwarePodReconciler annotates ingester pods of zone-aware
+// LokiStacks with the availability zone of their nodes.
+type LokiStackZoneAwarePodReconciler struct {
+	client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+	// Recorder records missing node topology keys as events on the LokiStack.
+	Recorder record.EventRecorder
+}
+
+// Reconcile annotates the requested ingester pod with the availability zone of its node.
+func (r *LokiStackZoneAwarePodReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	err := handlers.AnnotatePodWithAvailabilityZone(ctx, req, r.Client, r.Recorder)
+	if err != nil {
+		// Retry with the exponential backoff of the controller,
+		// e.g. until the node is labeled with the topology keys.
+		return ctrl.Result{}, err
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *LokiStackZoneAwarePodReconciler) SetupWithManager(mgr manager.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr)
+	return r.buildController(k8s.NewCtrlBuilder(b))
+}
+
+func (r *LokiStackZoneAwarePodReconciler) buildController(bld k8s.Builder) error {
+	return bld.
+		For(&corev1.Pod{}, unannotatedIngesterPodPred).
+		Complete(r)
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLokiStackZoneAwarePodController_RegistersPodsForCreateOrUpdate(t *testing.T) {
+	b := &k8sfakes.FakeBuilder{}
+	k := &k8sfakes.FakeClient{}
+	c := &LokiStackZoneAwarePodReconciler{Client: k, Scheme: scheme}
+
+	b.ForReturns(b)
+
+	err := c.buildController(b)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, b.ForCallCount())
+
+	obj, opts := b.ForArgsForCall(0)
+	require.Equal(t, &corev1.Pod{}, obj)
+	require.Equal(t, opts[0], unannotatedIngesterPodPred)
+}
+
+func TestIsUnannotatedIngesterPod(t *testing.T) {
+	table := []struct {
+		desc        string
+		component   string
+		nodeName    string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			desc:      "scheduled ingester without annotation",
+			component: manifests.LabelIngesterComponent,
+			nodeName:  "node-a",
+			want:      true,
+		},
+		{
+			desc:      "unscheduled ingester",
+			component: manifests.LabelIngesterComponent,
+		},
+		{
+			desc:      "annotated ingester",
+			component: manifests.LabelIngesterComponent,
+			nodeName:  "node-a",
+			annotations: map[string]string{
+				manifests.AnnotationAvailabilityZone: "zone-a",
+			},
+		},
+		{
+			desc:      "other component",
+			component: manifests.LabelQuerierComponent,
+			nodeName:  "node-a",
+		},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.desc, func(t *testing.T) {
+			t.Parallel()
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      manifests.ComponentLabels(tst.component, "my-stack"),
+					Annotations: tst.annotations,
+				},
+				Spec: corev1.PodSpec{
+					NodeName: tst.nodeName,
+				},
+			}
+
+			require.Equal(t, tst.want, isUnannotatedIngesterPod(pod))
+		})
+	}
+}
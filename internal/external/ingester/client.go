@@ -0,0 +1,64 @@
+package ingester
+
+import (
+	"context"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+// RingState is the state of an instance in the ingester ring.
+type RingState string
+
+const (
+	// RingStatePending is the state of an ingester waiting to join the ring.
+	RingStatePending RingState = "PENDING"
+	// RingStateJoining is the state of an ingester inserting its tokens into the ring.
+	RingStateJoining RingState = "JOINING"
+	// RingStateActive is the state of an ingester accepting writes.
+	RingStateActive RingState = "ACTIVE"
+	// RingStateLeaving is the state of an ingester flushing its chunks before shutdown.
+	RingStateLeaving RingState = "LEAVING"
+	// RingStateLeft is the state of an ingester that left a memberlist ring
+	// and is kept as a tombstone until it expires.
+	RingStateLeft RingState = "LEFT"
+	// RingStateUnhealthy is reported for instances that missed their heartbeats.
+	RingStateUnhealthy RingState = "UNHEALTHY"
+	// RingStateAbsent is returned for instances not registered in the ring.
+	RingStateAbsent RingState = ""
+)
+
+// Endpoint addresses the HTTP server of a Loki component.
+type Endpoint struct {
+	// URL is the base URL of the HTTP server, e.g. http://10.128.0.12:3100.
+	URL string
+	// ServerName is used to verify the serving certificate of https URLs.
+	ServerName string
+}
+
+// RingMember describes an instance registered in the ring.
+type RingMember struct {
+	// ID is the instance ID, i.e. the ingester pod name.
+	ID    string
+	State RingState
+	// Zone is empty unless zone awareness is enabled.
+	Zone string
+	// Tokens is the number of tokens registered by the instance.
+	Tokens int
+	// Ownership is the fraction of the token range owned by the instance.
+	Ownership float64
+}
+
+// Client calls the lifecycle endpoints of Loki ingesters.
+//
+//counterfeiter:generate . Client
+type Client interface {
+	// Flush flushes the in-memory chunks of the ingester.
+	Flush(ctx context.Context, ep Endpoint) error
+	// Shutdown flushes the in-memory chunks of the ingester and shuts it down,
+	// leaving the ring.
+	Shutdown(ctx context.Context, ep Endpoint) error
+	// RingState returns the state of the instance in the ring served by the endpoint.
+	RingState(ctx context.Context, ep Endpoint, instance string) (RingState, error)
+	// Ring returns all instances of the ring served by the endpoint.
+	Ring(ctx context.Context, ep Endpoint) ([]RingMember, error)
+}
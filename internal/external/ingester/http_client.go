@@ -0,0 +1,226 @@
+package ingester
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+)
+
+// Loki 2.4 endpoints, see https://grafana.com/docs/loki/v2.4.x/api/
+const (
+	flushPath         = "/flush"
+	flushShutdownPath = "/ingester/flush_shutdown"
+	ringPath          = "/ring"
+)
+
+// ringResponse is the JSON representation of the ring page.
+type ringResponse struct {
+	Shards []struct {
+		ID     string   `json:"id"`
+		State  string   `json:"state"`
+		Zone   string   `json:"zone"`
+		Tokens []uint32 `json:"tokens"`
+	} `json:"shards"`
+}
+
+// idleConnTimeout closes connections to ingesters and distributors
+// no longer called, e.g. after their pods are replaced.
+const idleConnTimeout = 90 * time.Second
+
+type httpClient struct {
+	timeout time.Duration
+	rootCAs *x509.CertPool
+
+	mu sync.Mutex
+	// clients holds a client per server name, as pooled connections
+	// are only reused for requests to the same server name.
+	clients map[string]*http.Client
+}
+
+// NewHTTPClient returns a Client calling the ingester HTTP endpoints. Serving
+// certificates of https endpoints are verified against the system roots and the
+// certificates in caFile, e.g. the service CA mounted into the operator pod. A
+// missing caFile is ignored.
+func NewHTTPClient(timeout time.Duration, caFile string) (Client, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if caFile != "" {
+		pem, err := ioutil.ReadFile(caFile)
+		switch {
+		case err == nil:
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, kverrors.New("failed to parse CA certificates", "file", caFile)
+			}
+		case !os.IsNotExist(err):
+			return nil, kverrors.Wrap(err, "failed to read CA certificates", "file", caFile)
+		}
+	}
+
+	return &httpClient{
+		timeout: timeout,
+		rootCAs: pool,
+		clients: map[string]*http.Client{},
+	}, nil
+}
+
+func (c *httpClient) Flush(ctx context.Context, ep Endpoint) error {
+	res, err := c.do(ctx, http.MethodPost, ep, flushPath)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (c *httpClient) Shutdown(ctx context.Context, ep Endpoint) error {
+	res, err := c.do(ctx, http.MethodPost, ep, flushShutdownPath)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (c *httpClient) RingState(ctx context.Context, ep Endpoint, instance string) (RingState, error) {
+	members, err := c.Ring(ctx, ep)
+	if err != nil {
+		return RingStateAbsent, err
+	}
+
+	for _, m := range members {
+		if m.ID == instance {
+			return m.State, nil
+		}
+	}
+
+	return RingStateAbsent, nil
+}
+
+func (c *httpClient) Ring(ctx context.Context, ep Endpoint) ([]RingMember, error) {
+	res, err := c.do(ctx, http.MethodGet, ep, ringPath)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var ring ringResponse
+	if err := json.NewDecoder(res.Body).Decode(&ring); err != nil {
+		return nil, kverrors.Wrap(err, "failed to decode ring", "url", ep.URL)
+	}
+
+	tokens := map[string][]uint32{}
+	members := make([]RingMember, 0, len(ring.Shards))
+	for _, s := range ring.Shards {
+		tokens[s.ID] = s.Tokens
+		members = append(members, RingMember{
+			ID:     s.ID,
+			State:  RingState(s.State),
+			Zone:   s.Zone,
+			Tokens: len(s.Tokens),
+		})
+	}
+
+	ownership := tokenOwnership(tokens)
+	for i := range members {
+		members[i].Ownership = ownership[members[i].ID]
+	}
+
+	return members, nil
+}
+
+// tokenOwnership returns the fraction of the 32-bit token range owned by each
+// instance. A token owns the range from the preceding token in the ring up to itself.
+func tokenOwnership(tokens map[string][]uint32) map[string]float64 {
+	type owned struct {
+		token    uint32
+		instance string
+	}
+
+	var all []owned
+	for id, ts := range tokens {
+		for _, t := range ts {
+			all = append(all, owned{token: t, instance: id})
+		}
+	}
+
+	ownership := map[string]float64{}
+	if len(all) == 0 {
+		return ownership
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].token < all[j].token })
+
+	const ringSize = float64(math.MaxUint32) + 1
+	for i, o := range all {
+		// The first token owns the range wrapping around from the last token.
+		prev := all[len(all)-1].token
+		if i > 0 {
+			prev = all[i-1].token
+		}
+		// uint32 subtraction wraps around as the ring does.
+		size := float64(o.token - prev)
+		if len(all) == 1 {
+			size = ringSize
+		}
+		ownership[o.instance] += size / ringSize
+	}
+
+	return ownership
+}
+
+func (c *httpClient) do(ctx context.Context, method string, ep Endpoint, path string) (*http.Response, error) {
+	u := strings.TrimSuffix(ep.URL, "/") + path
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to create request", "url", u)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.client(ep.ServerName).Do(req)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "request failed", "url", u)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		_ = res.Body.Close()
+		return nil, kverrors.New("unexpected response status", "url", u, "status", res.StatusCode)
+	}
+
+	return res, nil
+}
+
+// client returns the client for endpoints verified against serverName.
+func (c *httpClient) client(serverName string) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hc, ok := c.clients[serverName]; ok {
+		return hc
+	}
+
+	hc := &http.Client{
+		Timeout: c.timeout,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    c.rootCAs,
+				ServerName: serverName,
+			},
+			IdleConnTimeout: idleConnTimeout,
+		},
+	}
+	c.clients[serverName] = hc
+
+	return hc
+}
@@ -0,0 +1,160 @@
+package ingester_test
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPClient_FlushAndShutdown(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		paths = append(paths, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := ingester.NewHTTPClient(time.Second, "")
+	require.NoError(t, err)
+
+	ep := ingester.Endpoint{URL: srv.URL}
+	require.NoError(t, c.Flush(context.TODO(), ep))
+	require.NoError(t, c.Shutdown(context.TODO(), ep))
+	require.Equal(t, []string{"/flush", "/ingester/flush_shutdown"}, paths)
+}
+
+func TestHTTPClient_Shutdown_WhenStatusNotOK_ReturnsError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := ingester.NewHTTPClient(time.Second, "")
+	require.NoError(t, err)
+
+	err = c.Shutdown(context.TODO(), ingester.Endpoint{URL: srv.URL})
+	require.Error(t, err)
+}
+
+func TestHTTPClient_RingState(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ring", r.URL.Path)
+		require.Equal(t, "application/json", r.Header.Get("Accept"))
+		_, _ = fmt.Fprint(w, `{
+			"shards": [
+				{"id": "loki-ingester-my-stack-0", "state": "ACTIVE", "address": "10.128.0.12:9095"},
+				{"id": "loki-ingester-my-stack-1", "state": "LEAVING", "address": "10.128.0.13:9095"}
+			],
+			"now": "2021-06-01T10:00:00Z"
+		}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := ingester.NewHTTPClient(time.Second, "")
+	require.NoError(t, err)
+
+	table := []struct {
+		instance string
+		want     ingester.RingState
+	}{
+		{instance: "loki-ingester-my-stack-0", want: ingester.RingStateActive},
+		{instance: "loki-ingester-my-stack-1", want: ingester.RingStateLeaving},
+		{instance: "loki-ingester-my-stack-2", want: ingester.RingStateAbsent},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.instance, func(t *testing.T) {
+			t.Parallel()
+
+			state, err := c.RingState(context.TODO(), ingester.Endpoint{URL: srv.URL}, tst.instance)
+			require.NoError(t, err)
+			require.Equal(t, tst.want, state)
+		})
+	}
+}
+
+func TestHTTPClient_ReusesConnections(t *testing.T) {
+	var conns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	c, err := ingester.NewHTTPClient(time.Second, "")
+	require.NoError(t, err)
+
+	ep := ingester.Endpoint{URL: srv.URL}
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Flush(context.TODO(), ep))
+	}
+	require.EqualValues(t, 1, atomic.LoadInt32(&conns))
+}
+
+func TestHTTPClient_VerifiesServerName(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+
+	caFile := filepath.Join(t.TempDir(), "service-ca.crt")
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, ioutil.WriteFile(caFile, ca, 0o600))
+
+	c, err := ingester.NewHTTPClient(time.Second, caFile)
+	require.NoError(t, err)
+
+	// The test server certificate is issued for example.com.
+	require.Error(t, c.Flush(context.TODO(), ingester.Endpoint{URL: srv.URL, ServerName: "loki.example.org"}))
+	require.NoError(t, c.Flush(context.TODO(), ingester.Endpoint{URL: srv.URL, ServerName: "example.com"}))
+	require.Error(t, c.Flush(context.TODO(), ingester.Endpoint{URL: srv.URL, ServerName: "loki.example.org"}))
+}
+
+func TestNewHTTPClient_WhenCAFileMissing_IgnoresFile(t *testing.T) {
+	_, err := ingester.NewHTTPClient(time.Second, "/does/not/exist/service-ca.crt")
+	require.NoError(t, err)
+}
+
+func TestHTTPClient_Ring(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/ring", r.URL.Path)
+		_, _ = fmt.Fprint(w, `{
+			"shards": [
+				{"id": "loki-ingester-my-stack-0", "state": "ACTIVE", "zone": "eu-west-1a", "tokens": [1073741824, 3221225472]},
+				{"id": "loki-ingester-my-stack-1", "state": "UNHEALTHY", "zone": "eu-west-1b", "tokens": [2147483648]},
+				{"id": "loki-ingester-my-stack-2", "state": "PENDING", "tokens": []}
+			]
+		}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	c, err := ingester.NewHTTPClient(time.Second, "")
+	require.NoError(t, err)
+
+	members, err := c.Ring(context.TODO(), ingester.Endpoint{URL: srv.URL})
+	require.NoError(t, err)
+
+	want := []ingester.RingMember{
+		{ID: "loki-ingester-my-stack-0", State: ingester.RingStateActive, Zone: "eu-west-1a", Tokens: 2, Ownership: 0.75},
+		{ID: "loki-ingester-my-stack-1", State: ingester.RingStateUnhealthy, Zone: "eu-west-1b", Tokens: 1, Ownership: 0.25},
+		{ID: "loki-ingester-my-stack-2", State: ingester.RingStatePending},
+	}
+	require.Equal(t, want, members)
+}
@@ -0,0 +1,356 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+)
+
+const (
+	awsDefaultRegion   = "us-east-1"
+	awsSigningAlgo     = "AWS4-HMAC-SHA256"
+	awsDateLayout      = "20060102T150405Z"
+	awsEmptyBodySHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	azureStorageVersion = "2019-12-12"
+
+	gcsReadOnlyScope = "https://www.googleapis.com/auth/devstorage.read_only"
+	gcsJWTGrantType  = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+	swiftObjectStoreType = "object-store"
+)
+
+// signAWSV4 adds an AWS signature version 4 for the S3 service to a request without body.
+// See https://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-header-based-auth.html
+func signAWSV4(req *http.Request, cfg *storage.S3StorageConfig, now time.Time) {
+	region := cfg.Region
+	if region == "" {
+		region = awsDefaultRegion
+	}
+
+	date := now.UTC().Format(awsDateLayout)
+	scope := strings.Join([]string{date[:8], region, "s3", "aws4_request"}, "/")
+
+	req.Header.Set("X-Amz-Date", date)
+	req.Header.Set("X-Amz-Content-Sha256", awsEmptyBodySHA256)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		awsCanonicalQuery(req.URL.Query()),
+		"host:" + host,
+		"x-amz-content-sha256:" + awsEmptyBodySHA256,
+		"x-amz-date:" + date,
+		"",
+		signedHeaders,
+		awsEmptyBodySHA256,
+	}, "\n")
+
+	hash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		awsSigningAlgo,
+		date,
+		scope,
+		hex.EncodeToString(hash[:]),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+cfg.AccessKeySecret), date[:8])
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgo, cfg.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func awsCanonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var params []string
+	for _, k := range keys {
+		values := q[k]
+		sort.Strings(values)
+		for _, v := range values {
+			params = append(params, awsEscape(k)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// signAzureSharedKey adds a shared key signature of the storage account to a request without body.
+// See https://docs.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func signAzureSharedKey(req *http.Request, cfg *storage.AzureStorageConfig, now time.Time) error {
+	key, err := base64.StdEncoding.DecodeString(cfg.AccountKey)
+	if err != nil {
+		return kverrors.Wrap(err, "invalid azure account key", "account", cfg.AccountName)
+	}
+
+	req.Header.Set("X-Ms-Date", now.UTC().Format(http.TimeFormat))
+	req.Header.Set("X-Ms-Version", azureStorageVersion)
+
+	resource := "/" + cfg.AccountName + req.URL.EscapedPath()
+	q := req.URL.Query()
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values := q[k]
+		sort.Strings(values)
+		resource += fmt.Sprintf("\n%s:%s", strings.ToLower(k), strings.Join(values, ","))
+	}
+
+	// The standard headers from Content-Encoding to Range are empty.
+	stringToSign := req.Method + "\n" + strings.Repeat("\n", 11) +
+		"x-ms-date:" + req.Header.Get("X-Ms-Date") + "\n" +
+		"x-ms-version:" + azureStorageVersion + "\n" +
+		resource
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(stringToSign))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	req.Header.Set("Authorization", fmt.Sprintf("SharedKey %s:%s", cfg.AccountName, signature))
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// gcsServiceAccountKey is the subset of a google service account key file used for probing.
+type gcsServiceAccountKey struct {
+	Type        string `json:"type"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// gcsAccessToken exchanges a JWT signed by the service account key for an OAuth2 access token.
+// It returns an empty token for other credential types, e.g. workload identity.
+// See https://developers.google.com/identity/protocols/oauth2/service-account#authorizingrequests
+func gcsAccessToken(ctx context.Context, c *http.Client, keyJSON string, now time.Time) (string, error) {
+	var key gcsServiceAccountKey
+	if err := json.Unmarshal([]byte(keyJSON), &key); err != nil {
+		return "", kverrors.Wrap(err, "invalid gcs service account key")
+	}
+
+	if key.Type != "service_account" {
+		return "", nil
+	}
+
+	assertion, err := gcsJWT(key, now)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {gcsJWTGrantType},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to create gcs token request", "url", key.TokenURI)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", kverrors.New("gcs service account authentication failed", "url", key.TokenURI, "status", res.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&token); err != nil {
+		return "", kverrors.Wrap(err, "failed to decode gcs token response", "url", key.TokenURI)
+	}
+
+	return token.AccessToken, nil
+}
+
+func gcsJWT(key gcsServiceAccountKey, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return "", kverrors.New("invalid gcs service account private key", "email", key.ClientEmail)
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return "", kverrors.Wrap(err, "invalid gcs service account private key", "email", key.ClientEmail)
+		}
+	}
+
+	pk, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", kverrors.New("gcs service account private key is not an RSA key", "email", key.ClientEmail)
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   key.ClientEmail,
+		"scope": gcsReadOnlyScope,
+		"aud":   key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawURLEncoding
+	unsigned := enc.EncodeToString(header) + "." + enc.EncodeToString(claims)
+
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, pk, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to sign gcs token request", "email", key.ClientEmail)
+	}
+
+	return unsigned + "." + enc.EncodeToString(signature), nil
+}
+
+// swiftAuth authenticates against the keystone v3 identity service and returns the token
+// and the URL of the object store for the configured region.
+// See https://docs.openstack.org/api-ref/identity/v3/#password-authentication-with-scoped-authorization
+func swiftAuth(ctx context.Context, c *http.Client, cfg *storage.SwiftStorageConfig) (token, endpoint string, err error) {
+	u, err := url.Parse(cfg.AuthURL)
+	if err != nil {
+		return "", "", kverrors.Wrap(err, "invalid swift auth url", "url", cfg.AuthURL)
+	}
+	if path.Base(u.Path) == "v3" {
+		u.Path = path.Join(u.Path, "auth", "tokens")
+	} else {
+		u.Path = path.Join("/", u.Path, "v3", "auth", "tokens")
+	}
+
+	body, err := json.Marshal(swiftAuthRequest(cfg))
+	if err != nil {
+		return "", "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return "", "", kverrors.Wrap(err, "failed to create swift auth request", "url", u.String())
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := c.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		return "", "", kverrors.New("swift authentication failed", "url", u.String(), "status", res.StatusCode)
+	}
+
+	var auth struct {
+		Token struct {
+			Catalog []struct {
+				Type      string `json:"type"`
+				Endpoints []struct {
+					Interface string `json:"interface"`
+					Region    string `json:"region"`
+					URL       string `json:"url"`
+				} `json:"endpoints"`
+			} `json:"catalog"`
+		} `json:"token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&auth); err != nil {
+		return "", "", kverrors.Wrap(err, "failed to decode swift auth response", "url", u.String())
+	}
+
+	for _, svc := range auth.Token.Catalog {
+		if svc.Type != swiftObjectStoreType {
+			continue
+		}
+		for _, ep := range svc.Endpoints {
+			if ep.Interface == "public" && (cfg.Region == "" || ep.Region == cfg.Region) {
+				return res.Header.Get("X-Subject-Token"), ep.URL, nil
+			}
+		}
+	}
+
+	return "", "", kverrors.New("no swift object store endpoint in service catalog", "url", u.String(), "region", cfg.Region)
+}
+
+func swiftAuthRequest(cfg *storage.SwiftStorageConfig) map[string]interface{} {
+	auth := map[string]interface{}{
+		"identity": map[string]interface{}{
+			"methods": []string{"password"},
+			"password": map[string]interface{}{
+				"user": map[string]interface{}{
+					"name":     cfg.Username,
+					"domain":   map[string]string{"name": cfg.UserDomainName},
+					"password": cfg.Password,
+				},
+			},
+		},
+	}
+
+	if cfg.ProjectName != "" {
+		domain := cfg.ProjectDomainName
+		if domain == "" {
+			domain = cfg.UserDomainName
+		}
+		auth["scope"] = map[string]interface{}{
+			"project": map[string]interface{}{
+				"name":   cfg.ProjectName,
+				"domain": map[string]string{"name": domain},
+			},
+		}
+	}
+
+	return map[string]interface{}{"auth": auth}
+}
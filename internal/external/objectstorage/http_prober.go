@@ -0,0 +1,263 @@
+package objectstorage
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+)
+
+const (
+	defaultAzureEndpointSuffix = "core.windows.net"
+	gcsEndpoint                = "https://storage.googleapis.com"
+)
+
+// probeTarget is a single HEAD request issued for a bucket.
+type probeTarget struct {
+	bucket string
+	// request returns the HEAD request for the bucket, authenticated with
+	// the object storage credentials if signed is set.
+	request func(ctx context.Context, c *http.Client) (*http.Request, error)
+	signed  bool
+}
+
+type httpProber struct {
+	timeout time.Duration
+}
+
+// NewHTTPProber returns a Prober issuing HEAD requests to each configured bucket,
+// signed with the credentials of the object storage secret. It detects unreachable
+// endpoints, missing buckets and rejected credentials. With S3 STS the requests are
+// sent anonymously, so only unreachable endpoints and missing buckets are detected.
+func NewHTTPProber(timeout time.Duration) Prober {
+	return &httpProber{timeout: timeout}
+}
+
+func (p *httpProber) Probe(ctx context.Context, opts storage.Options) error {
+	targets, err := probeTargets(opts)
+	if err != nil {
+		return err
+	}
+
+	tlsConfig, err := probeTLSConfig(opts.TLS)
+	if err != nil {
+		return err
+	}
+
+	c := &http.Client{
+		Timeout: p.timeout,
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	defer c.CloseIdleConnections()
+
+	for _, t := range targets {
+		if err := t.probe(ctx, c); err != nil {
+			return &BucketError{Bucket: t.bucket, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func (t probeTarget) probe(ctx context.Context, c *http.Client) error {
+	req, err := t.request(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return kverrors.New("bucket not found", "url", req.URL.String())
+	case !t.signed:
+		// Anonymous requests to existing private buckets are denied.
+		return nil
+	case res.StatusCode == http.StatusUnauthorized, res.StatusCode == http.StatusForbidden:
+		return kverrors.New("access denied, check the object storage credentials", "url", req.URL.String(), "status", res.StatusCode)
+	case res.StatusCode >= http.StatusMultipleChoices:
+		return kverrors.New("unexpected response", "url", req.URL.String(), "status", res.StatusCode)
+	}
+
+	return nil
+}
+
+func probeTargets(opts storage.Options) ([]probeTarget, error) {
+	switch opts.SharedStore {
+	case lokiv1beta1.ObjectStorageSecretAzure:
+		if opts.Azure == nil {
+			break
+		}
+		return []probeTarget{azureProbeTarget(opts.Azure)}, nil
+	case lokiv1beta1.ObjectStorageSecretGCS:
+		if opts.GCS == nil {
+			break
+		}
+		return []probeTarget{gcsProbeTarget(opts.GCS)}, nil
+	case lokiv1beta1.ObjectStorageSecretS3:
+		if opts.S3 == nil {
+			break
+		}
+		return s3ProbeTargets(opts.S3)
+	case lokiv1beta1.ObjectStorageSecretSwift:
+		if opts.Swift == nil {
+			break
+		}
+		return []probeTarget{swiftProbeTarget(opts.Swift)}, nil
+	}
+
+	return nil, kverrors.New("missing object storage config for probe", "type", opts.SharedStore)
+}
+
+func azureProbeTarget(cfg *storage.AzureStorageConfig) probeTarget {
+	suffix := cfg.EndpointSuffix
+	if suffix == "" {
+		suffix = defaultAzureEndpointSuffix
+	}
+	u := fmt.Sprintf("https://%s.blob.%s/%s?restype=container", cfg.AccountName, suffix, cfg.Container)
+
+	return probeTarget{
+		bucket: cfg.Container,
+		signed: true,
+		request: func(ctx context.Context, _ *http.Client) (*http.Request, error) {
+			req, err := newProbeRequest(ctx, u)
+			if err != nil {
+				return nil, err
+			}
+			if err := signAzureSharedKey(req, cfg, time.Now()); err != nil {
+				return nil, err
+			}
+			return req, nil
+		},
+	}
+}
+
+func gcsProbeTarget(cfg *storage.GCSStorageConfig) probeTarget {
+	u := fmt.Sprintf("%s/%s", gcsEndpoint, cfg.Bucket)
+
+	return probeTarget{
+		bucket: cfg.Bucket,
+		signed: true,
+		request: func(ctx context.Context, c *http.Client) (*http.Request, error) {
+			token, err := gcsAccessToken(ctx, c, cfg.ServiceAccountKey, time.Now())
+			if err != nil {
+				return nil, err
+			}
+
+			req, err := newProbeRequest(ctx, u)
+			if err != nil {
+				return nil, err
+			}
+			if token != "" {
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+			return req, nil
+		},
+	}
+}
+
+func s3ProbeTargets(cfg *storage.S3StorageConfig) ([]probeTarget, error) {
+	endpoint := cfg.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "invalid s3 endpoint", "endpoint", cfg.Endpoint)
+	}
+
+	// Short-lived STS credentials are only available to the component pods.
+	signed := cfg.STS == nil
+
+	var targets []probeTarget
+	for _, bucket := range strings.Split(cfg.Buckets, ",") {
+		// Loki addresses buckets path-style, see s3forcepathstyle.
+		bu := *u
+		bu.Path = path.Join("/", u.Path, bucket)
+		bucketURL := bu.String()
+
+		targets = append(targets, probeTarget{
+			bucket: bucket,
+			signed: signed,
+			request: func(ctx context.Context, _ *http.Client) (*http.Request, error) {
+				req, err := newProbeRequest(ctx, bucketURL)
+				if err != nil {
+					return nil, err
+				}
+				if signed {
+					signAWSV4(req, cfg, time.Now())
+				}
+				return req, nil
+			},
+		})
+	}
+
+	return targets, nil
+}
+
+func swiftProbeTarget(cfg *storage.SwiftStorageConfig) probeTarget {
+	return probeTarget{
+		bucket: cfg.Container,
+		signed: true,
+		request: func(ctx context.Context, c *http.Client) (*http.Request, error) {
+			token, endpoint, err := swiftAuth(ctx, c, cfg)
+			if err != nil {
+				return nil, err
+			}
+
+			req, err := newProbeRequest(ctx, strings.TrimSuffix(endpoint, "/")+"/"+url.PathEscape(cfg.Container))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("X-Auth-Token", token)
+			return req, nil
+		},
+	}
+}
+
+func newProbeRequest(ctx context.Context, u string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, u, nil)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to create probe request", "url", u)
+	}
+	return req, nil
+}
+
+// probeTLSConfig returns the TLS client config for probing the object storage endpoint.
+// Like Loki, it verifies the endpoint certificate against the custom CA only, if given.
+func probeTLSConfig(cfg *storage.TLSConfig) (*tls.Config, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+
+	tc := &tls.Config{
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(cfg.CABundle)) {
+			return nil, kverrors.New("failed to parse object storage CA certificate", "name", cfg.CA, "key", cfg.CAKey)
+		}
+		tc.RootCAs = pool
+	}
+
+	return tc, nil
+}
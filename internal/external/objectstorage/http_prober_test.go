@@ -0,0 +1,315 @@
+package objectstorage_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProber_S3(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodHead, r.Method)
+
+		auth := r.Header.Get("Authorization")
+		switch {
+		case r.URL.Path == "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		case auth == "", strings.Contains(auth, "Credential=wrong/"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	type test struct {
+		name        string
+		endpoint    string
+		buckets     string
+		accessKeyID string
+		sts         *storage.STSConfig
+		wantBucket  string
+	}
+	table := []test{
+		{
+			name:        "reachable buckets",
+			endpoint:    srv.URL,
+			buckets:     "loki-1,loki-2",
+			accessKeyID: "id",
+		},
+		{
+			name:        "missing bucket",
+			endpoint:    srv.URL,
+			buckets:     "loki-1,missing",
+			accessKeyID: "id",
+			wantBucket:  "missing",
+		},
+		{
+			name:        "rejected credentials",
+			endpoint:    srv.URL,
+			buckets:     "loki-1",
+			accessKeyID: "wrong",
+			wantBucket:  "loki-1",
+		},
+		{
+			name:     "anonymous requests with sts",
+			endpoint: srv.URL,
+			buckets:  "loki-1",
+			sts:      &storage.STSConfig{RoleARN: "arn"},
+		},
+		{
+			name:        "unreachable endpoint",
+			endpoint:    closed.URL,
+			buckets:     "loki-1",
+			accessKeyID: "id",
+			wantBucket:  "loki-1",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := storage.Options{
+				SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+				S3: &storage.S3StorageConfig{
+					Endpoint:        tst.endpoint,
+					Buckets:         tst.buckets,
+					STS:             tst.sts,
+					AccessKeyID:     tst.accessKeyID,
+					AccessKeySecret: "secret",
+				},
+			}
+
+			err := objectstorage.NewHTTPProber(time.Second).Probe(context.TODO(), opts)
+			if tst.wantBucket == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var bucketErr *objectstorage.BucketError
+			require.True(t, errors.As(err, &bucketErr))
+			require.Equal(t, tst.wantBucket, bucketErr.Bucket)
+		})
+	}
+}
+
+func TestHTTPProber_Swift(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v3/auth/tokens":
+			require.Equal(t, http.MethodPost, r.Method)
+
+			var body struct {
+				Auth struct {
+					Identity struct {
+						Password struct {
+							User struct {
+								Password string `json:"password"`
+							} `json:"user"`
+						} `json:"password"`
+					} `json:"identity"`
+				} `json:"auth"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			if body.Auth.Identity.Password.User.Password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Header().Set("X-Subject-Token", "token")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, `{"token":{"catalog":[{"type":"object-store","endpoints":[{"interface":"public","region":"RegionOne","url":"%s/swift/v1"}]}]}}`, srv.URL)
+		case "/swift/v1/loki":
+			require.Equal(t, http.MethodHead, r.Method)
+			require.Equal(t, "token", r.Header.Get("X-Auth-Token"))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	type test struct {
+		name      string
+		password  string
+		container string
+		region    string
+		wantErr   bool
+	}
+	table := []test{
+		{
+			name:      "reachable container",
+			password:  "secret",
+			container: "loki",
+		},
+		{
+			name:      "missing container",
+			password:  "secret",
+			container: "missing",
+			wantErr:   true,
+		},
+		{
+			name:      "rejected credentials",
+			password:  "wrong",
+			container: "loki",
+			wantErr:   true,
+		},
+		{
+			name:      "no endpoint in region",
+			password:  "secret",
+			container: "loki",
+			region:    "RegionTwo",
+			wantErr:   true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := storage.Options{
+				SharedStore: lokiv1beta1.ObjectStorageSecretSwift,
+				Swift: &storage.SwiftStorageConfig{
+					AuthURL:        srv.URL + "/v3",
+					Username:       "loki",
+					UserDomainName: "Default",
+					ProjectName:    "logging",
+					Region:         tst.region,
+					Container:      tst.container,
+					Password:       tst.password,
+				},
+			}
+
+			err := objectstorage.NewHTTPProber(time.Second).Probe(context.TODO(), opts)
+			if !tst.wantErr {
+				require.NoError(t, err)
+				return
+			}
+
+			var bucketErr *objectstorage.BucketError
+			require.True(t, errors.As(err, &bucketErr))
+			require.Equal(t, tst.container, bucketErr.Bucket)
+		})
+	}
+}
+
+func TestHTTPProber_GCS_WhenTokenRequestRejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+		require.Len(t, strings.Split(r.PostForm.Get("assertion"), "."), 3)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+
+	pk, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(pk)
+	require.NoError(t, err)
+
+	key, err := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"client_email": "loki@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    srv.URL + "/token",
+	})
+	require.NoError(t, err)
+
+	opts := storage.Options{
+		SharedStore: lokiv1beta1.ObjectStorageSecretGCS,
+		GCS: &storage.GCSStorageConfig{
+			Bucket:            "loki",
+			ServiceAccountKey: string(key),
+		},
+	}
+
+	err = objectstorage.NewHTTPProber(time.Second).Probe(context.TODO(), opts)
+
+	var bucketErr *objectstorage.BucketError
+	require.True(t, errors.As(err, &bucketErr))
+	require.Equal(t, "loki", bucketErr.Bucket)
+}
+
+func TestHTTPProber_WithCustomCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	ca := string(pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	}))
+
+	type test struct {
+		name    string
+		tls     *storage.TLSConfig
+		wantErr bool
+	}
+	table := []test{
+		{
+			name:    "without CA",
+			wantErr: true,
+		},
+		{
+			name: "with CA",
+			tls:  &storage.TLSConfig{CABundle: ca},
+		},
+		{
+			name:    "with invalid CA",
+			tls:     &storage.TLSConfig{CABundle: "invalid"},
+			wantErr: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := storage.Options{
+				SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+				S3: &storage.S3StorageConfig{
+					Endpoint: srv.URL,
+					Buckets:  "loki",
+				},
+				TLS: tst.tls,
+			}
+
+			err := objectstorage.NewHTTPProber(time.Second).Probe(context.TODO(), opts)
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHTTPProber_MissingConfig(t *testing.T) {
+	opts := storage.Options{
+		SharedStore: lokiv1beta1.ObjectStorageSecretGCS,
+	}
+
+	err := objectstorage.NewHTTPProber(time.Second).Probe(context.TODO(), opts)
+	require.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+)
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
+
+// Prober checks that the object storage buckets configured for a LokiStack are reachable.
+//
+//counterfeiter:generate . Prober
+type Prober interface {
+	Probe(ctx context.Context, opts storage.Options) error
+}
+
+// BucketError is returned by a Prober when a bucket cannot be reached.
+type BucketError struct {
+	Bucket string
+	Err    error
+}
+
+func (e *BucketError) Error() string {
+	return fmt.Sprintf("bucket %s: %s", e.Bucket, e.Err)
+}
+
+// Unwrap returns the underlying probe error.
+func (e *BucketError) Unwrap() error {
+	return e.Err
+}
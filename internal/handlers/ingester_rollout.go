@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// annotationShutdownRequestedAt records when the operator shut down an ingester pod.
+	annotationShutdownRequestedAt = "loki.openshift.io/shutdown-requested-at"
+
+	// ingesterShutdownTimeout is the time an ingester may take to flush its chunks
+	// and leave the ring, before the rollout is reported as stuck.
+	ingesterShutdownTimeout = 15 * time.Minute
+)
+
+// RolloutIngesters replaces the outdated pods of the ingester statefulset one at a time.
+// Each pod is flushed and shut down via the ingester HTTP API, and deleted once it left
+// the ring, so that the statefulset controller recreates it with the current revision.
+// It returns true while the rollout is in progress.
+func RolloutIngesters(ctx context.Context, req ctrl.Request, k k8s.Client, c ingester.Client, flags manifests.FeatureFlags) (bool, error) {
+	ll := log.WithValues("lokistack", req.NamespacedName, "event", "rolloutIngesters")
+
+	var stack lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &stack); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	var sts appsv1.StatefulSet
+	key := client.ObjectKey{Name: manifests.IngesterName(stack.Name), Namespace: stack.Namespace}
+	if err := k.Get(ctx, key, &sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, kverrors.Wrap(err, "failed to lookup statefulset", "name", key)
+	}
+
+	revision := sts.Status.UpdateRevision
+	if sts.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType || revision == "" {
+		return false, nil
+	}
+
+	pods := &corev1.PodList{}
+	opts := []client.ListOption{
+		client.MatchingLabels(manifests.ComponentLabels(manifests.LabelIngesterComponent, stack.Name)),
+		client.InNamespace(stack.Namespace),
+	}
+	if err := k.List(ctx, pods, opts...); err != nil {
+		return false, kverrors.Wrap(err, "failed to list ingester pods", "name", req.NamespacedName)
+	}
+
+	rollout := &lokiv1beta1.RolloutStatus{
+		Revision: revision,
+		Replicas: int32(len(pods.Items)),
+	}
+
+	var outdated []corev1.Pod
+	for _, pod := range pods.Items {
+		if pod.Labels[appsv1.ControllerRevisionHashLabelKey] == revision {
+			rollout.UpdatedReplicas++
+			continue
+		}
+		outdated = append(outdated, pod)
+	}
+
+	if len(outdated) == 0 {
+		rollout.Phase = lokiv1beta1.RolloutPhaseComplete
+		return false, status.SetIngesterRolloutStatus(ctx, k, req, rollout)
+	}
+
+	pod := nextIngesterPod(outdated)
+	rollout.Pod = pod.Name
+
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+
+	phase, err := rolloutIngesterPod(ctx, k, c, &stack, &pod, pods.Items, replicas, flags)
+	if err != nil {
+		return true, err
+	}
+	ll.Info("ingester rollout in progress", "pod", pod.Name, "phase", phase)
+
+	rollout.Phase = phase
+	return true, status.SetIngesterRolloutStatus(ctx, k, req, rollout)
+}
+
+// rolloutIngesterPod advances the restart of a single ingester pod by one step.
+func rolloutIngesterPod(
+	ctx context.Context,
+	k k8s.Client,
+	c ingester.Client,
+	stack *lokiv1beta1.LokiStack,
+	pod *corev1.Pod,
+	pods []corev1.Pod,
+	replicas int32,
+	flags manifests.FeatureFlags,
+) (lokiv1beta1.RolloutPhase, error) {
+	requestedAt, ok := pod.Annotations[annotationShutdownRequestedAt]
+	if !ok {
+		// Never take down an ingester while another one is missing,
+		// otherwise streams may lose their write quorum.
+		if int32(len(pods)) < replicas || !allPodsReady(pods) {
+			return lokiv1beta1.RolloutPhaseWaitingForReadyPods, nil
+		}
+
+		ep := ingesterEndpoint(stack, pod, flags)
+		if err := c.Flush(ctx, ep); err != nil {
+			return "", kverrors.Wrap(err, "failed to flush ingester", "pod", pod.Name)
+		}
+		if err := c.Shutdown(ctx, ep); err != nil {
+			return "", kverrors.Wrap(err, "failed to shut down ingester", "pod", pod.Name)
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[annotationShutdownRequestedAt] = time.Now().UTC().Format(time.RFC3339)
+
+		if err := k.Patch(ctx, pod, patch); err != nil {
+			return "", kverrors.Wrap(err, "failed to annotate ingester pod", "pod", pod.Name)
+		}
+
+		return lokiv1beta1.RolloutPhaseFlushing, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, requestedAt)
+	if err != nil {
+		return "", kverrors.Wrap(err, "invalid shutdown annotation", "pod", pod.Name, "value", requestedAt)
+	}
+
+	// The ingester process exits after leaving the ring. The kubelet restarts
+	// the container, which joins the ring again with the outdated revision.
+	if restartedSince(pod, since) {
+		return lokiv1beta1.RolloutPhaseWaitingForReadyPods, deleteIngesterPod(ctx, k, pod)
+	}
+
+	state, err := c.RingState(ctx, distributorEndpoint(stack, flags), pod.Name)
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to lookup ingester ring state", "pod", pod.Name)
+	}
+
+	switch state {
+	case ingester.RingStateAbsent, ingester.RingStateLeft:
+		return lokiv1beta1.RolloutPhaseWaitingForReadyPods, deleteIngesterPod(ctx, k, pod)
+	}
+
+	if time.Since(since) > ingesterShutdownTimeout {
+		return lokiv1beta1.RolloutPhaseShutdownTimedOut, nil
+	}
+
+	if state == ingester.RingStateLeaving {
+		return lokiv1beta1.RolloutPhaseLeaving, nil
+	}
+	return lokiv1beta1.RolloutPhaseFlushing, nil
+}
+
+func deleteIngesterPod(ctx context.Context, k k8s.Client, pod *corev1.Pod) error {
+	// Guard against deleting a pod recreated with the same name in the meantime.
+	err := k.Delete(ctx, pod, client.Preconditions{UID: &pod.UID})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return kverrors.Wrap(err, "failed to delete ingester pod", "pod", pod.Name)
+	}
+	return nil
+}
+
+// nextIngesterPod returns the pod already shutting down if any, otherwise
+// the pod with the highest ordinal, as the statefulset controller would do.
+func nextIngesterPod(pods []corev1.Pod) corev1.Pod {
+	for _, pod := range pods {
+		if _, ok := pod.Annotations[annotationShutdownRequestedAt]; ok {
+			return pod
+		}
+	}
+
+	sort.Slice(pods, func(i, j int) bool {
+		return podOrdinal(pods[i].Name) > podOrdinal(pods[j].Name)
+	})
+	return pods[0]
+}
+
+func podOrdinal(name string) int {
+	i := strings.LastIndex(name, "-")
+	n, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func allPodsReady(pods []corev1.Pod) bool {
+	for _, pod := range pods {
+		if !isPodReady(&pod) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func restartedSince(pod *corev1.Pod, since time.Time) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		t := cs.LastTerminationState.Terminated
+		if t != nil && !t.FinishedAt.Time.Before(since) {
+			return true
+		}
+	}
+	return false
+}
+
+func ingesterEndpoint(stack *lokiv1beta1.LokiStack, pod *corev1.Pod, flags manifests.FeatureFlags) ingester.Endpoint {
+	// Pods are addressed by IP, but serve the certificate of the ingester service.
+	return ingester.Endpoint{
+		URL:        fmt.Sprintf("%s://%s", httpScheme(flags), net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(manifests.LokiHTTPPort))),
+		ServerName: fmt.Sprintf("%s.%s.svc", manifests.IngesterHTTPServiceName(stack.Name), stack.Namespace),
+	}
+}
+
+func distributorEndpoint(stack *lokiv1beta1.LokiStack, flags manifests.FeatureFlags) ingester.Endpoint {
+	host := fmt.Sprintf("%s.%s.svc", manifests.DistributorHTTPServiceName(stack.Name), stack.Namespace)
+	return ingester.Endpoint{
+		URL:        fmt.Sprintf("%s://%s", httpScheme(flags), net.JoinHostPort(host, strconv.Itoa(manifests.LokiHTTPPort))),
+		ServerName: host,
+	}
+}
+
+func httpScheme(flags manifests.FeatureFlags) string {
+	if flags.EnableTLSServiceMonitorConfig {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,373 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/ViaQ/loki-operator/internal/external/ingester/ingesterfakes"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const shutdownAnnotation = "loki.openshift.io/shutdown-requested-at"
+
+func newRolloutPod(name, revision string, ready bool) corev1.Pod {
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+
+	return corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "some-ns",
+			UID:       types.UID(name),
+			Labels: map[string]string{
+				appsv1.ControllerRevisionHashLabelKey: revision,
+			},
+		},
+		Status: corev1.PodStatus{
+			PodIP: "10.128.0.12",
+			Conditions: []corev1.PodCondition{
+				{
+					Type:   corev1.PodReady,
+					Status: readyStatus,
+				},
+			},
+		},
+	}
+}
+
+func withShutdownRequested(pod corev1.Pod, at time.Time) corev1.Pod {
+	pod.Annotations = map[string]string{
+		shutdownAnnotation: at.UTC().Format(time.RFC3339),
+	}
+	return pod
+}
+
+func setupIngesterRollout(pods []corev1.Pod) (*k8sfakes.FakeClient, *k8sfakes.FakeStatusWriter) {
+	stack := &lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      manifests.IngesterName(stack.Name),
+			Namespace: "some-ns",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: pointer.Int32Ptr(int32(len(pods))),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.OnDeleteStatefulSetStrategyType,
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			UpdateRevision: "rev-2",
+		},
+	}
+
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		switch object.(type) {
+		case *lokiv1beta1.LokiStack:
+			k.SetClientObject(object, stack)
+			return nil
+		case *appsv1.StatefulSet:
+			if name.Name == sts.Name {
+				k.SetClientObject(object, sts)
+				return nil
+			}
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		k.SetClientObjectList(list, &corev1.PodList{Items: pods})
+		return nil
+	}
+
+	return k, sw
+}
+
+func rolloutStatus(t *testing.T, sw *k8sfakes.FakeStatusWriter) *lokiv1beta1.RolloutStatus {
+	require.Equal(t, 1, sw.UpdateCallCount())
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	return obj.(*lokiv1beta1.LokiStack).Status.IngesterRollout
+}
+
+var rolloutRequest = ctrl.Request{
+	NamespacedName: types.NamespacedName{
+		Name:      "my-stack",
+		Namespace: "some-ns",
+	},
+}
+
+func TestRolloutIngesters_WhenAllPodsUpdated_SetComplete(t *testing.T) {
+	k, sw := setupIngesterRollout([]corev1.Pod{
+		newRolloutPod("loki-ingester-my-stack-0", "rev-2", true),
+		newRolloutPod("loki-ingester-my-stack-1", "rev-2", true),
+	})
+	c := &ingesterfakes.FakeClient{}
+
+	inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.False(t, inProgress)
+
+	want := &lokiv1beta1.RolloutStatus{
+		Revision:        "rev-2",
+		Replicas:        2,
+		UpdatedReplicas: 2,
+		Phase:           lokiv1beta1.RolloutPhaseComplete,
+	}
+	require.Equal(t, want, rolloutStatus(t, sw))
+	require.Zero(t, c.ShutdownCallCount())
+}
+
+func TestRolloutIngesters_WhenStatefulSetNotFound_DoNothing(t *testing.T) {
+	k, sw := setupIngesterRollout(nil)
+	k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+		if _, ok := object.(*lokiv1beta1.LokiStack); ok {
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+	c := &ingesterfakes.FakeClient{}
+
+	inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.False(t, inProgress)
+	require.Zero(t, sw.UpdateCallCount())
+}
+
+func TestRolloutIngesters_WhenPodNotReady_Wait(t *testing.T) {
+	k, sw := setupIngesterRollout([]corev1.Pod{
+		newRolloutPod("loki-ingester-my-stack-0", "rev-1", false),
+		newRolloutPod("loki-ingester-my-stack-1", "rev-1", true),
+	})
+	c := &ingesterfakes.FakeClient{}
+
+	inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.True(t, inProgress)
+
+	got := rolloutStatus(t, sw)
+	require.Equal(t, lokiv1beta1.RolloutPhaseWaitingForReadyPods, got.Phase)
+	require.Equal(t, "loki-ingester-my-stack-1", got.Pod)
+	require.Zero(t, c.FlushCallCount())
+	require.Zero(t, c.ShutdownCallCount())
+}
+
+func TestRolloutIngesters_WhenAllPodsReady_ShutdownHighestOrdinal(t *testing.T) {
+	type test struct {
+		name    string
+		flags   manifests.FeatureFlags
+		wantURL string
+	}
+	table := []test{
+		{
+			name:    "http",
+			wantURL: "http://10.128.0.12:3100",
+		},
+		{
+			name:    "https",
+			flags:   manifests.FeatureFlags{EnableTLSServiceMonitorConfig: true},
+			wantURL: "https://10.128.0.12:3100",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, sw := setupIngesterRollout([]corev1.Pod{
+				newRolloutPod("loki-ingester-my-stack-0", "rev-1", true),
+				newRolloutPod("loki-ingester-my-stack-2", "rev-1", true),
+				newRolloutPod("loki-ingester-my-stack-1", "rev-2", true),
+			})
+			c := &ingesterfakes.FakeClient{}
+
+			inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, tst.flags)
+			require.NoError(t, err)
+			require.True(t, inProgress)
+
+			wantEndpoint := ingester.Endpoint{
+				URL:        tst.wantURL,
+				ServerName: "loki-ingester-http-my-stack.some-ns.svc",
+			}
+			require.Equal(t, 1, c.FlushCallCount())
+			_, ep := c.FlushArgsForCall(0)
+			require.Equal(t, wantEndpoint, ep)
+
+			require.Equal(t, 1, c.ShutdownCallCount())
+			_, ep = c.ShutdownArgsForCall(0)
+			require.Equal(t, wantEndpoint, ep)
+
+			require.Equal(t, 1, k.PatchCallCount())
+			_, obj, _, _ := k.PatchArgsForCall(0)
+			require.Equal(t, "loki-ingester-my-stack-2", obj.GetName())
+			require.Contains(t, obj.GetAnnotations(), shutdownAnnotation)
+
+			want := &lokiv1beta1.RolloutStatus{
+				Revision:        "rev-2",
+				Replicas:        3,
+				UpdatedReplicas: 1,
+				Pod:             "loki-ingester-my-stack-2",
+				Phase:           lokiv1beta1.RolloutPhaseFlushing,
+			}
+			require.Equal(t, want, rolloutStatus(t, sw))
+		})
+	}
+}
+
+func TestRolloutIngesters_WhenShutdownFails_ReturnError(t *testing.T) {
+	k, sw := setupIngesterRollout([]corev1.Pod{
+		newRolloutPod("loki-ingester-my-stack-0", "rev-1", true),
+	})
+	c := &ingesterfakes.FakeClient{}
+	c.ShutdownReturns(errors.New("connection refused"))
+
+	inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+	require.Error(t, err)
+	require.True(t, inProgress)
+	require.Zero(t, k.PatchCallCount())
+	require.Zero(t, sw.UpdateCallCount())
+}
+
+func TestRolloutIngesters_WhenShuttingDown(t *testing.T) {
+	now := time.Now()
+
+	restarted := withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", false), now.Add(-time.Minute))
+	restarted.Status.ContainerStatuses = []corev1.ContainerStatus{
+		{
+			Name: "loki-ingester",
+			LastTerminationState: corev1.ContainerState{
+				Terminated: &corev1.ContainerStateTerminated{
+					FinishedAt: metav1.NewTime(now),
+				},
+			},
+		},
+	}
+
+	type test struct {
+		name       string
+		pod        corev1.Pod
+		ringState  ingester.RingState
+		wantPhase  lokiv1beta1.RolloutPhase
+		wantDelete bool
+	}
+	table := []test{
+		{
+			name:      "active",
+			pod:       withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", true), now),
+			ringState: ingester.RingStateActive,
+			wantPhase: lokiv1beta1.RolloutPhaseFlushing,
+		},
+		{
+			name:      "leaving",
+			pod:       withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", false), now),
+			ringState: ingester.RingStateLeaving,
+			wantPhase: lokiv1beta1.RolloutPhaseLeaving,
+		},
+		{
+			name:      "leaving for too long",
+			pod:       withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", false), now.Add(-time.Hour)),
+			ringState: ingester.RingStateLeaving,
+			wantPhase: lokiv1beta1.RolloutPhaseShutdownTimedOut,
+		},
+		{
+			name:       "left",
+			pod:        withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", false), now),
+			ringState:  ingester.RingStateLeft,
+			wantPhase:  lokiv1beta1.RolloutPhaseWaitingForReadyPods,
+			wantDelete: true,
+		},
+		{
+			name:       "gone",
+			pod:        withShutdownRequested(newRolloutPod("loki-ingester-my-stack-1", "rev-1", false), now),
+			ringState:  ingester.RingStateAbsent,
+			wantPhase:  lokiv1beta1.RolloutPhaseWaitingForReadyPods,
+			wantDelete: true,
+		},
+		{
+			name:       "restarted and joined again",
+			pod:        restarted,
+			ringState:  ingester.RingStateActive,
+			wantPhase:  lokiv1beta1.RolloutPhaseWaitingForReadyPods,
+			wantDelete: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			k, sw := setupIngesterRollout([]corev1.Pod{
+				newRolloutPod("loki-ingester-my-stack-0", "rev-1", true),
+				tst.pod,
+				newRolloutPod("loki-ingester-my-stack-2", "rev-2", true),
+			})
+			c := &ingesterfakes.FakeClient{}
+			c.RingStateReturns(tst.ringState, nil)
+
+			inProgress, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+			require.NoError(t, err)
+			require.True(t, inProgress)
+
+			require.Zero(t, c.ShutdownCallCount())
+
+			got := rolloutStatus(t, sw)
+			require.Equal(t, tst.wantPhase, got.Phase)
+			require.Equal(t, "loki-ingester-my-stack-1", got.Pod)
+
+			if !tst.wantDelete {
+				require.Zero(t, k.DeleteCallCount())
+				return
+			}
+
+			require.Equal(t, 1, k.DeleteCallCount())
+			_, obj, opts := k.DeleteArgsForCall(0)
+			require.Equal(t, "loki-ingester-my-stack-1", obj.GetName())
+			uid := types.UID("loki-ingester-my-stack-1")
+			require.Contains(t, opts, client.Preconditions{UID: &uid})
+		})
+	}
+}
+
+func TestRolloutIngesters_RingStateQueriesDistributor(t *testing.T) {
+	k, _ := setupIngesterRollout([]corev1.Pod{
+		withShutdownRequested(newRolloutPod("loki-ingester-my-stack-0", "rev-1", true), time.Now()),
+	})
+	c := &ingesterfakes.FakeClient{}
+	c.RingStateReturns(ingester.RingStateLeaving, nil)
+
+	_, err := handlers.RolloutIngesters(context.TODO(), rolloutRequest, k, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, c.RingStateCallCount())
+	_, ep, instance := c.RingStateArgsForCall(0)
+	require.Equal(t, ingester.Endpoint{
+		URL:        "http://loki-distributor-http-my-stack.some-ns.svc:3100",
+		ServerName: "loki-distributor-http-my-stack.some-ns.svc",
+	}, ep)
+	require.Equal(t, "loki-ingester-my-stack-0", instance)
+}
@@ -0,0 +1,74 @@
+package autoscaling
+
+import (
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+)
+
+// ComponentError describes the component with an invalid autoscaling spec.
+type ComponentError struct {
+	Component string
+	Err       error
+}
+
+// Error returns the error message including the component name.
+func (e *ComponentError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Component, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ComponentError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the autoscaling specs of the LokiStack components. The following rules apply:
+// - Only the distributor, querier, query frontend and gateway support autoscaling.
+// - The min replicas must not exceed the max replicas.
+func Validate(spec *lokiv1beta1.LokiTemplateSpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	for _, c := range []struct {
+		name string
+		spec *lokiv1beta1.LokiComponentSpec
+	}{
+		{name: "compactor", spec: spec.Compactor},
+		{name: "ingester", spec: spec.Ingester},
+		{name: "ruler", spec: spec.Ruler},
+		{name: "indexGateway", spec: spec.IndexGateway},
+	} {
+		if c.spec != nil && c.spec.Autoscaling != nil {
+			return &ComponentError{
+				Component: c.name,
+				Err:       kverrors.New("autoscaling is not supported for stateful components"),
+			}
+		}
+	}
+
+	for _, c := range []struct {
+		name string
+		spec *lokiv1beta1.LokiComponentSpec
+	}{
+		{name: "distributor", spec: spec.Distributor},
+		{name: "querier", spec: spec.Querier},
+		{name: "queryFrontend", spec: spec.QueryFrontend},
+		{name: "gateway", spec: spec.Gateway},
+	} {
+		if c.spec == nil || c.spec.Autoscaling == nil {
+			continue
+		}
+
+		a := c.spec.Autoscaling
+		if a.MinReplicas > a.MaxReplicas {
+			return &ComponentError{
+				Component: c.name,
+				Err:       kverrors.New("min replicas must not exceed max replicas", "min", a.MinReplicas, "max", a.MaxReplicas),
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package autoscaling_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/autoscaling"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		name    string
+		spec    *lokiv1beta1.LokiTemplateSpec
+		wantErr string
+	}
+	table := []test{
+		{
+			name: "no template",
+		},
+		{
+			name: "autoscaled stateless components",
+			spec: &lokiv1beta1.LokiTemplateSpec{
+				Distributor: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{MinReplicas: 1, MaxReplicas: 3},
+				},
+				Querier: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{MinReplicas: 2, MaxReplicas: 2},
+				},
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 3,
+				},
+			},
+		},
+		{
+			name: "autoscaled stateful component",
+			spec: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{MinReplicas: 1, MaxReplicas: 3},
+				},
+			},
+			wantErr: "ingester: autoscaling is not supported for stateful components",
+		},
+		{
+			name: "min replicas exceed max replicas",
+			spec: &lokiv1beta1.LokiTemplateSpec{
+				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{MinReplicas: 4, MaxReplicas: 2},
+				},
+			},
+			wantErr: "queryFrontend: min replicas must not exceed max replicas",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := autoscaling.Validate(tst.spec)
+			if tst.wantErr != "" {
+				require.EqualError(t, err, tst.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package caches
+
+import (
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+)
+
+// CacheError describes the invalid cache of the LokiStack caches spec.
+type CacheError struct {
+	Cache string
+	Err   error
+}
+
+// Error returns the error message including the cache name.
+func (e *CacheError) Error() string {
+	return fmt.Sprintf("%s cache: %s", e.Cache, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *CacheError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the caches of the LokiStack. The following rules apply:
+// - Managed caches must be of type memcached.
+// - Caches not managed by the operator must define at least one endpoint.
+func Validate(spec *lokiv1beta1.CachesSpec) error {
+	if spec == nil {
+		return nil
+	}
+
+	for _, c := range []struct {
+		name string
+		spec *lokiv1beta1.CacheSpec
+	}{
+		{name: "chunks", spec: spec.Chunks},
+		{name: "indexQueries", spec: spec.IndexQueries},
+		{name: "results", spec: spec.Results},
+	} {
+		if err := validateCache(c.spec); err != nil {
+			return &CacheError{Cache: c.name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func validateCache(c *lokiv1beta1.CacheSpec) error {
+	if c == nil {
+		return nil
+	}
+
+	if c.Managed {
+		if c.Type != lokiv1beta1.CacheMemcached {
+			return kverrors.New("managed caches must be of type memcached", "type", c.Type)
+		}
+		return nil
+	}
+
+	if len(c.Endpoints) == 0 {
+		return kverrors.New("missing endpoints for external cache")
+	}
+
+	for _, e := range c.Endpoints {
+		if e == "" {
+			return kverrors.New("empty cache endpoint")
+		}
+	}
+
+	return nil
+}
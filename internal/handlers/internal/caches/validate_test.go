@@ -0,0 +1,82 @@
+package caches_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/caches"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	type test struct {
+		name    string
+		spec    *lokiv1beta1.CachesSpec
+		wantErr string
+	}
+	table := []test{
+		{
+			name: "no caches",
+		},
+		{
+			name: "managed and external caches",
+			spec: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+				IndexQueries: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{"redis.cache.svc:6379"},
+				},
+				Results: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheMemcached,
+					Endpoints: []string{"memcached-0.cache.svc:11211", "memcached-1.cache.svc:11211"},
+				},
+			},
+		},
+		{
+			name: "managed redis cache",
+			spec: &lokiv1beta1.CachesSpec{
+				Results: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheRedis,
+					Managed: true,
+				},
+			},
+			wantErr: "results cache: managed caches must be of type memcached",
+		},
+		{
+			name: "external cache without endpoints",
+			spec: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type: lokiv1beta1.CacheMemcached,
+				},
+			},
+			wantErr: "chunks cache: missing endpoints for external cache",
+		},
+		{
+			name: "external cache with empty endpoint",
+			spec: &lokiv1beta1.CachesSpec{
+				IndexQueries: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{""},
+				},
+			},
+			wantErr: "indexQueries cache: empty cache endpoint",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := caches.Validate(tst.spec)
+			if tst.wantErr != "" {
+				require.EqualError(t, err, tst.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/ViaQ/loki-operator/internal/status"
 	configv1 "github.com/openshift/api/config/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -17,13 +18,13 @@ import (
 // clusters to auto-create redirect URLs for OpenShift Auth or an error.
 // If the config.openshift.io/DNS object is not found the whole lokistack
 // resoure is set to a degraded state.
-func GetOpenShiftBaseDomain(ctx context.Context, k k8s.Client, req ctrl.Request) (string, error) {
+func GetOpenShiftBaseDomain(ctx context.Context, k k8s.Client, rec record.EventRecorder, req ctrl.Request) (string, error) {
 	var cluster configv1.DNS
 	key := client.ObjectKey{Name: "cluster"}
 	if err := k.Get(ctx, key, &cluster); err != nil {
 
 		if apierrors.IsNotFound(err) {
-			statusErr := status.SetDegradedCondition(ctx, k, req,
+			statusErr := status.SetDegradedCondition(ctx, k, rec, req,
 				"Missing cluster DNS configuration to read base domain",
 				lokiv1beta1.ReasonMissingGatewayOpenShiftBaseDomain,
 			)
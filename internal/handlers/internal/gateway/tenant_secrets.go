@@ -14,6 +14,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -25,6 +26,7 @@ import (
 func GetTenantSecrets(
 	ctx context.Context,
 	k k8s.Client,
+	rec record.EventRecorder,
 	req ctrl.Request,
 	stack *lokiv1beta1.LokiStack,
 ) ([]*manifests.TenantSecrets, error) {
@@ -37,7 +39,7 @@ func GetTenantSecrets(
 		key := client.ObjectKey{Name: tenant.OIDC.Secret.Name, Namespace: req.Namespace}
 		if err := k.Get(ctx, key, &gatewaySecret); err != nil {
 			if apierrors.IsNotFound(err) {
-				statusErr := status.SetDegradedCondition(ctx, k, req,
+				statusErr := status.SetDegradedCondition(ctx, k, rec, req,
 					fmt.Sprintf("Missing secrets for tenant %s", tenant.TenantName),
 					lokiv1beta1.ReasonMissingGatewayTenantSecret,
 				)
@@ -54,7 +56,7 @@ func GetTenantSecrets(
 		var ts *manifests.TenantSecrets
 		ts, err := secrets.ExtractGatewaySecret(&gatewaySecret, tenant.TenantName)
 		if err != nil {
-			statusErr := status.SetDegradedCondition(ctx, k, req,
+			statusErr := status.SetDegradedCondition(ctx, k, rec, req,
 				"Invalid gateway tenant secret contents",
 				lokiv1beta1.ReasonInvalidGatewayTenantSecret,
 			)
@@ -13,6 +13,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -66,7 +67,7 @@ func TestGetTenantSecrets_StaticMode(t *testing.T) {
 		return nil
 	}
 
-	ts, err := GetTenantSecrets(context.TODO(), k, r, s)
+	ts, err := GetTenantSecrets(context.TODO(), k, &record.FakeRecorder{}, r, s)
 	require.NoError(t, err)
 
 	expected := []*manifests.TenantSecrets{
@@ -129,7 +130,7 @@ func TestGetTenantSecrets_DynamicMode(t *testing.T) {
 		return nil
 	}
 
-	ts, err := GetTenantSecrets(context.TODO(), k, r, s)
+	ts, err := GetTenantSecrets(context.TODO(), k, &record.FakeRecorder{}, r, s)
 	require.NoError(t, err)
 
 	expected := []*manifests.TenantSecrets{
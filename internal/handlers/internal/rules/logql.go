@@ -0,0 +1,251 @@
+package rules
+
+import (
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/prometheus/common/model"
+)
+
+var closingBrackets = map[byte]byte{
+	')': '(',
+	']': '[',
+	'}': '{',
+}
+
+// functions are the LogQL keywords followed by parentheses, i.e. the aggregation
+// and conversion functions, grouping clauses and set operators of Loki 2.x.
+var functions = map[string]bool{
+	// Log range aggregations
+	"rate":               true,
+	"count_over_time":    true,
+	"bytes_rate":         true,
+	"bytes_over_time":    true,
+	"absent_over_time":   true,
+	"sum_over_time":      true,
+	"avg_over_time":      true,
+	"max_over_time":      true,
+	"min_over_time":      true,
+	"stdvar_over_time":   true,
+	"stddev_over_time":   true,
+	"quantile_over_time": true,
+	"first_over_time":    true,
+	"last_over_time":     true,
+	// Vector aggregations
+	"sum":       true,
+	"avg":       true,
+	"min":       true,
+	"max":       true,
+	"stddev":    true,
+	"stdvar":    true,
+	"count":     true,
+	"bottomk":   true,
+	"topk":      true,
+	"sort":      true,
+	"sort_desc": true,
+	// Functions
+	"label_replace":    true,
+	"vector":           true,
+	"bytes":            true,
+	"duration":         true,
+	"duration_seconds": true,
+	"ip":               true,
+	// Grouping and set operators
+	"by":          true,
+	"without":     true,
+	"on":          true,
+	"ignoring":    true,
+	"group_left":  true,
+	"group_right": true,
+	"and":         true,
+	"or":          true,
+	"unless":      true,
+}
+
+// validateLogQL performs a lightweight syntax check of a LogQL expression.
+// It checks that brackets and string literals are balanced, that the
+// expression contains at least one stream selector with valid label matchers,
+// that range durations are valid and that only known functions are called.
+// It is not a full LogQL parser, checking the remaining syntax and the
+// semantics of the expression is left to the Loki ruler.
+func validateLogQL(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return kverrors.New("expression must not be empty")
+	}
+
+	var (
+		open      []byte
+		openAt    []int
+		selectors int
+	)
+
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch c {
+		case '"', '`':
+			end, err := skipString(expr, i)
+			if err != nil {
+				return err
+			}
+			i = end
+		case '(':
+			if name := precedingIdentifier(expr, i); name != "" && !functions[name] {
+				return kverrors.New("unknown function", "function", name)
+			}
+			open = append(open, c)
+			openAt = append(openAt, i)
+		case '[':
+			open = append(open, c)
+			openAt = append(openAt, i)
+		case '{':
+			for _, o := range open {
+				if o == '{' {
+					return kverrors.New("nested stream selector")
+				}
+			}
+			open = append(open, c)
+			openAt = append(openAt, i)
+		case ')', ']', '}':
+			if len(open) == 0 || open[len(open)-1] != closingBrackets[c] {
+				return kverrors.New("unbalanced brackets", "bracket", string(c))
+			}
+			start := openAt[len(openAt)-1] + 1
+			open = open[:len(open)-1]
+			openAt = openAt[:len(openAt)-1]
+
+			switch c {
+			case ']':
+				if _, err := model.ParseDuration(strings.TrimSpace(expr[start:i])); err != nil {
+					return kverrors.Wrap(err, "invalid range duration", "range", expr[start:i])
+				}
+			case '}':
+				if err := validateStreamSelector(expr[start:i]); err != nil {
+					return err
+				}
+				selectors++
+			}
+		}
+	}
+
+	if len(open) > 0 {
+		return kverrors.New("unclosed brackets", "bracket", string(open[len(open)-1]))
+	}
+
+	if selectors == 0 {
+		return kverrors.New("expression must contain a stream selector")
+	}
+
+	return nil
+}
+
+// validateStreamSelector checks the comma-separated label matchers
+// of a stream selector, e.g. `app="foo", env=~"prod|dev"`.
+func validateStreamSelector(s string) error {
+	matchers := 0
+	i := skipSpaces(s, 0)
+
+	for i < len(s) {
+		// Label name
+		n := i
+		for i < len(s) && isLabelChar(s[i], i == n) {
+			i++
+		}
+		if i == n {
+			return kverrors.New("invalid label name in stream selector", "selector", s)
+		}
+		i = skipSpaces(s, i)
+
+		// Match operator
+		switch {
+		case strings.HasPrefix(s[i:], "=~"), strings.HasPrefix(s[i:], "!~"), strings.HasPrefix(s[i:], "!="):
+			i += 2
+		case strings.HasPrefix(s[i:], "="):
+			i++
+		default:
+			return kverrors.New("invalid match operator in stream selector", "selector", s)
+		}
+		i = skipSpaces(s, i)
+
+		// Label value
+		if i >= len(s) || (s[i] != '"' && s[i] != '`') {
+			return kverrors.New("label value in stream selector must be quoted", "selector", s)
+		}
+		end, err := skipString(s, i)
+		if err != nil {
+			return err
+		}
+		matchers++
+
+		i = skipSpaces(s, end+1)
+		if i < len(s) {
+			if s[i] != ',' {
+				return kverrors.New("label matchers in stream selector must be comma-separated", "selector", s)
+			}
+			i = skipSpaces(s, i+1)
+			if i >= len(s) {
+				return kverrors.New("trailing comma in stream selector", "selector", s)
+			}
+		}
+	}
+
+	if matchers == 0 {
+		return kverrors.New("stream selector must contain at least one label matcher")
+	}
+
+	return nil
+}
+
+// precedingIdentifier returns the identifier in front of the opening
+// parenthesis at index i, or an empty string if there is none.
+func precedingIdentifier(s string, i int) string {
+	end := i
+	for end > 0 && strings.ContainsRune(" \t\r\n", rune(s[end-1])) {
+		end--
+	}
+
+	start := end
+	for start > 0 && isLabelChar(s[start-1], false) {
+		start--
+	}
+
+	// Numbers are no identifiers
+	if start == end || !isLabelChar(s[start], true) {
+		return ""
+	}
+	return s[start:end]
+}
+
+// skipString returns the index of the closing quote of the
+// string literal starting at index i.
+func skipString(s string, i int) (int, error) {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if quote == '"' {
+				j++
+			}
+		case quote:
+			return j, nil
+		}
+	}
+	return 0, kverrors.New("unterminated string literal")
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+		i++
+	}
+	return i
+}
+
+func isLabelChar(c byte, first bool) bool {
+	switch {
+	case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	default:
+		return false
+	}
+}
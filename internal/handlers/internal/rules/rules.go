@@ -0,0 +1,46 @@
+package rules
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// List returns the AlertingRule and RecordingRule resources in the given
+// namespace matching the selector of the rules spec. All resources in the
+// namespace are returned if no selector is defined.
+func List(
+	ctx context.Context,
+	k k8s.Client,
+	ns string,
+	rs *lokiv1beta1.RulesSpec,
+) ([]lokiv1beta1.AlertingRule, []lokiv1beta1.RecordingRule, error) {
+	opts := []client.ListOption{
+		client.InNamespace(ns),
+	}
+
+	if rs.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rs.Selector)
+		if err != nil {
+			return nil, nil, kverrors.Wrap(err, "failed to parse rules selector", "namespace", ns)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	var alertingRules lokiv1beta1.AlertingRuleList
+	if err := k.List(ctx, &alertingRules, opts...); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to list alerting rules", "namespace", ns)
+	}
+
+	var recordingRules lokiv1beta1.RecordingRuleList
+	if err := k.List(ctx, &recordingRules, opts...); err != nil {
+		return nil, nil, kverrors.Wrap(err, "failed to list recording rules", "namespace", ns)
+	}
+
+	return alertingRules.Items, recordingRules.Items, nil
+}
@@ -0,0 +1,61 @@
+package rules_test
+
+import (
+	"context"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/rules"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestList_MatchesSelectorInNamespace(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+
+	alertingRules := &lokiv1beta1.AlertingRuleList{
+		Items: []lokiv1beta1.AlertingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "alerts", Namespace: "some-ns"},
+			},
+		},
+	}
+	recordingRules := &lokiv1beta1.RecordingRuleList{
+		Items: []lokiv1beta1.RecordingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "recordings", Namespace: "some-ns"},
+			},
+		},
+	}
+
+	k.ListStub = func(_ context.Context, list client.ObjectList, opts ...client.ListOption) error {
+		lo := &client.ListOptions{}
+		lo.ApplyOptions(opts)
+
+		require.Equal(t, "some-ns", lo.Namespace)
+		require.Equal(t, "team=a", lo.LabelSelector.String())
+
+		switch list.(type) {
+		case *lokiv1beta1.AlertingRuleList:
+			k.SetClientObjectList(list, alertingRules)
+		case *lokiv1beta1.RecordingRuleList:
+			k.SetClientObjectList(list, recordingRules)
+		}
+		return nil
+	}
+
+	rs := &lokiv1beta1.RulesSpec{
+		Enabled: true,
+		Selector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{"team": "a"},
+		},
+	}
+
+	ar, rr, err := rules.List(context.TODO(), k, "some-ns", rs)
+	require.NoError(t, err)
+	require.Equal(t, alertingRules.Items, ar)
+	require.Equal(t, recordingRules.Items, rr)
+	require.Equal(t, 2, k.ListCallCount())
+}
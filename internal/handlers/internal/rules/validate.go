@@ -0,0 +1,138 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+)
+
+var (
+	// tenantIDRegexp restricts tenant IDs to valid directory names, because
+	// each tenant's rule files are mounted into a directory named by the ID.
+	tenantIDRegexp   = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+	metricNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+)
+
+// groupKey identifies a rule group of a tenant.
+type groupKey struct {
+	tenant string
+	name   string
+}
+
+// RuleError describes the invalid AlertingRule or RecordingRule resource.
+type RuleError struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+// Error returns the error message including the rule resource.
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("%s %s: %s", e.Kind, e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the alerting and recording rules before mounting them
+// into the ruler. The following rules apply:
+// - Tenant IDs must be valid directory names.
+// - Group names must be unique per tenant within all alerting respectively recording rules.
+// - Alert names must not be empty and records must be valid metric names.
+// - Expressions must pass a basic LogQL syntax check, see validateLogQL.
+func Validate(alertingRules []lokiv1beta1.AlertingRule, recordingRules []lokiv1beta1.RecordingRule) error {
+	groups := map[groupKey]bool{}
+	for _, r := range alertingRules {
+		if err := validateAlertingRule(r, groups); err != nil {
+			return &RuleError{Kind: "AlertingRule", Name: r.Name, Err: err}
+		}
+	}
+
+	groups = map[groupKey]bool{}
+	for _, r := range recordingRules {
+		if err := validateRecordingRule(r, groups); err != nil {
+			return &RuleError{Kind: "RecordingRule", Name: r.Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func validateAlertingRule(r lokiv1beta1.AlertingRule, groups map[groupKey]bool) error {
+	if err := validateTenantID(r.Spec.TenantID); err != nil {
+		return err
+	}
+
+	for _, g := range r.Spec.Groups {
+		if g == nil {
+			continue
+		}
+
+		key := groupKey{tenant: r.Spec.TenantID, name: g.Name}
+		if groups[key] {
+			return kverrors.New("duplicate rule group name", "tenant", r.Spec.TenantID, "group", g.Name)
+		}
+		groups[key] = true
+
+		for _, rule := range g.Rules {
+			if rule == nil {
+				continue
+			}
+
+			if rule.Alert == "" {
+				return kverrors.New("alert name must not be empty", "group", g.Name)
+			}
+
+			if err := validateLogQL(rule.Expr); err != nil {
+				return kverrors.Wrap(err, "invalid LogQL expression", "group", g.Name, "alert", rule.Alert)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateRecordingRule(r lokiv1beta1.RecordingRule, groups map[groupKey]bool) error {
+	if err := validateTenantID(r.Spec.TenantID); err != nil {
+		return err
+	}
+
+	for _, g := range r.Spec.Groups {
+		if g == nil {
+			continue
+		}
+
+		key := groupKey{tenant: r.Spec.TenantID, name: g.Name}
+		if groups[key] {
+			return kverrors.New("duplicate rule group name", "tenant", r.Spec.TenantID, "group", g.Name)
+		}
+		groups[key] = true
+
+		for _, rule := range g.Rules {
+			if rule == nil {
+				continue
+			}
+
+			if !metricNameRegexp.MatchString(rule.Record) {
+				return kverrors.New("record must be a valid metric name", "group", g.Name, "record", rule.Record)
+			}
+
+			if err := validateLogQL(rule.Expr); err != nil {
+				return kverrors.Wrap(err, "invalid LogQL expression", "group", g.Name, "record", rule.Record)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateTenantID(id string) error {
+	if id == "." || id == ".." || !tenantIDRegexp.MatchString(id) {
+		return kverrors.New("tenant id must be a valid directory name", "tenant", id)
+	}
+	return nil
+}
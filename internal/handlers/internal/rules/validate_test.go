@@ -0,0 +1,239 @@
+package rules_test
+
+import (
+	"errors"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/rules"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidate_LogQLExpressions(t *testing.T) {
+	type test struct {
+		name    string
+		expr    string
+		wantErr bool
+	}
+	table := []test{
+		{
+			name: "log range aggregation",
+			expr: `sum(rate({app="foo", env=~"prod|dev"} |= "error" [5m])) by (job) > 0.05`,
+		},
+		{
+			name: "binary operation of two selectors",
+			expr: `sum(rate({app="foo"} |= "error" [1m])) / sum(rate({app="foo"}[1m]))`,
+		},
+		{
+			name: "grouping and unwrapped range aggregation",
+			expr: `topk(10, sum by (app) (quantile_over_time(0.99, {app="foo"} | logfmt | unwrap duration(latency) [1m])))`,
+		},
+		{
+			name: "brackets and braces within strings",
+			expr: "count_over_time({app=\"foo\"} | line_format `{{.msg}} (x]` |~ \"\\\"}\" [5m])",
+		},
+		{
+			name:    "empty expression",
+			expr:    "  ",
+			wantErr: true,
+		},
+		{
+			name:    "missing stream selector",
+			expr:    `sum(rate([5m]))`,
+			wantErr: true,
+		},
+		{
+			name:    "empty stream selector",
+			expr:    `rate({}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "unbalanced brackets",
+			expr:    `sum(rate({app="foo"}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "mismatched brackets",
+			expr:    `sum(rate({app="foo"}[5m)])`,
+			wantErr: true,
+		},
+		{
+			name:    "unterminated string",
+			expr:    `rate({app="foo}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "unquoted label value",
+			expr:    `rate({app=foo}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid match operator",
+			expr:    `rate({app=="foo"}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "missing comma between matchers",
+			expr:    `rate({app="foo" env="prod"}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "trailing comma after matchers",
+			expr:    `rate({app="foo", }[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "nested stream selector",
+			expr:    `rate({app={env="prod"}}[5m])`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid range duration",
+			expr:    `rate({app="foo"}[5x])`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown function",
+			expr:    `rates({app="foo"}[5m])`,
+			wantErr: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := lokiv1beta1.RecordingRule{
+				ObjectMeta: metav1.ObjectMeta{Name: "recordings"},
+				Spec: lokiv1beta1.RecordingRuleSpec{
+					TenantID: "application",
+					Groups: []*lokiv1beta1.RecordingRuleGroup{
+						{
+							Name: "group",
+							Rules: []*lokiv1beta1.RecordingRuleGroupSpec{
+								{
+									Record: "foo:errors:rate5m",
+									Expr:   tst.expr,
+								},
+							},
+						},
+					},
+				},
+			}
+
+			err := rules.Validate(nil, []lokiv1beta1.RecordingRule{r})
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate_AlertingRules(t *testing.T) {
+	newRule := func(name, tenant, group, alert string) lokiv1beta1.AlertingRule {
+		return lokiv1beta1.AlertingRule{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: lokiv1beta1.AlertingRuleSpec{
+				TenantID: tenant,
+				Groups: []*lokiv1beta1.AlertingRuleGroup{
+					{
+						Name: group,
+						Rules: []*lokiv1beta1.AlertingRuleGroupSpec{
+							{
+								Alert: alert,
+								Expr:  `sum(rate({app="foo"} |= "error" [5m])) > 10`,
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name    string
+		rules   []lokiv1beta1.AlertingRule
+		wantErr bool
+	}
+	table := []test{
+		{
+			name: "valid rules",
+			rules: []lokiv1beta1.AlertingRule{
+				newRule("a", "application", "group-a", "HighErrorRate"),
+				newRule("b", "infrastructure", "group-b", "HighErrorRate"),
+			},
+		},
+		{
+			name: "duplicate group name",
+			rules: []lokiv1beta1.AlertingRule{
+				newRule("a", "application", "group", "HighErrorRate"),
+				newRule("b", "application", "group", "HighErrorRate"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "same group name for different tenants",
+			rules: []lokiv1beta1.AlertingRule{
+				newRule("a", "application", "group", "HighErrorRate"),
+				newRule("b", "infrastructure", "group", "HighErrorRate"),
+			},
+		},
+		{
+			name: "invalid tenant id",
+			rules: []lokiv1beta1.AlertingRule{
+				newRule("a", "../application", "group", "HighErrorRate"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty alert name",
+			rules: []lokiv1beta1.AlertingRule{
+				newRule("a", "application", "group", ""),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := rules.Validate(tst.rules, nil)
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate_InvalidRecordName(t *testing.T) {
+	r := lokiv1beta1.RecordingRule{
+		ObjectMeta: metav1.ObjectMeta{Name: "recordings"},
+		Spec: lokiv1beta1.RecordingRuleSpec{
+			TenantID: "application",
+			Groups: []*lokiv1beta1.RecordingRuleGroup{
+				{
+					Name: "group",
+					Rules: []*lokiv1beta1.RecordingRuleGroupSpec{
+						{
+							Record: "foo-errors",
+							Expr:   `sum(rate({app="foo"}[5m]))`,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := rules.Validate(nil, []lokiv1beta1.RecordingRule{r})
+
+	var ruleErr *rules.RuleError
+	require.True(t, errors.As(err, &ruleErr))
+	require.Equal(t, "RecordingRule", ruleErr.Kind)
+	require.Equal(t, "recordings", ruleErr.Name)
+}
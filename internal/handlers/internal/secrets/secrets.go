@@ -1,45 +1,248 @@
 package secrets
 
 import (
+	"crypto/sha1"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
 	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 
 	corev1 "k8s.io/api/core/v1"
 )
 
 // Extract reads a k8s secret into a manifest object storage struct if valid.
-func Extract(s *corev1.Secret) (*manifests.ObjectStorage, error) {
+// If sts is set, the static S3 access keys are not required in the secret.
+func Extract(s *corev1.Secret, secretType lokiv1beta1.ObjectStorageSecretType, sts *lokiv1beta1.ObjectStorageSTSSpec) (*storage.Options, error) {
+	hash, err := hashSecretData(s)
+	if err != nil {
+		return nil, kverrors.Wrap(err, "failed to hash storage secret", "name", s.Name)
+	}
+
+	storageOpts := storage.Options{
+		SecretName:  s.Name,
+		SecretSHA1:  hash,
+		SharedStore: secretType,
+	}
+
+	if sts != nil && secretType != lokiv1beta1.ObjectStorageSecretS3 {
+		return nil, kverrors.New("sts is only supported for object storage secret type s3", "type", secretType)
+	}
+
+	switch secretType {
+	case lokiv1beta1.ObjectStorageSecretAzure:
+		storageOpts.Azure, err = extractAzureConfigSecret(s)
+	case lokiv1beta1.ObjectStorageSecretGCS:
+		storageOpts.GCS, err = extractGCSConfigSecret(s)
+	case lokiv1beta1.ObjectStorageSecretS3:
+		storageOpts.S3, err = extractS3ConfigSecret(s, sts)
+	case lokiv1beta1.ObjectStorageSecretSwift:
+		storageOpts.Swift, err = extractSwiftConfigSecret(s)
+	default:
+		return nil, kverrors.New("unknown secret type", "type", secretType)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	return &storageOpts, nil
+}
+
+// hashSecretData returns the SHA1 hash of the secret data in key order.
+// Each key and value is prefixed with its length, so that moving bytes
+// between a key and its value changes the hash.
+func hashSecretData(s *corev1.Secret) (string, error) {
+	keys := make([]string, 0, len(s.Data))
+	for k := range s.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha1.New()
+	for _, k := range keys {
+		if err := writeLengthPrefixed(h, []byte(k)); err != nil {
+			return "", err
+		}
+		if err := writeLengthPrefixed(h, s.Data[k]); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+func writeLengthPrefixed(w io.Writer, b []byte) error {
+	var l [8]byte
+	binary.BigEndian.PutUint64(l[:], uint64(len(b)))
+	if _, err := w.Write(l[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+func extractAzureConfigSecret(s *corev1.Secret) (*storage.AzureStorageConfig, error) {
+	// Extract and validate mandatory fields
+	env, ok := s.Data["environment"]
+	if !ok {
+		return nil, kverrors.New("missing azure secret field: environment", "field", "environment")
+	}
+	container, ok := s.Data["container"]
+	if !ok {
+		return nil, kverrors.New("missing azure secret field: container", "field", "container")
+	}
+	name, ok := s.Data["account_name"]
+	if !ok {
+		return nil, kverrors.New("missing azure secret field: account_name", "field", "account_name")
+	}
+	key, ok := s.Data["account_key"]
+	if !ok {
+		return nil, kverrors.New("missing azure secret field: account_key", "field", "account_key")
+	}
+
+	// Extract and validate optional fields
+	suffix, ok := s.Data["endpoint_suffix"]
+	if !ok {
+		suffix = []byte("")
+	}
+
+	return &storage.AzureStorageConfig{
+		Env:            string(env),
+		Container:      string(container),
+		AccountName:    string(name),
+		EndpointSuffix: string(suffix),
+		AccountKey:     string(key),
+	}, nil
+}
+
+func extractGCSConfigSecret(s *corev1.Secret) (*storage.GCSStorageConfig, error) {
+	// Extract and validate mandatory fields
+	bucket, ok := s.Data["bucketname"]
+	if !ok {
+		return nil, kverrors.New("missing gcs secret field: bucketname", "field", "bucketname")
+	}
+
+	// Check if google authentication credentials is provided
+	key, ok := s.Data[storage.GCSFileName]
+	if !ok {
+		return nil, kverrors.New("missing gcs secret field: key.json", "field", storage.GCSFileName)
+	}
+
+	return &storage.GCSStorageConfig{
+		Bucket:            string(bucket),
+		ServiceAccountKey: string(key),
+	}, nil
+}
+
+func extractS3ConfigSecret(s *corev1.Secret, sts *lokiv1beta1.ObjectStorageSTSSpec) (*storage.S3StorageConfig, error) {
 	// Extract and validate mandatory fields
 	endpoint, ok := s.Data["endpoint"]
 	if !ok {
-		return nil, kverrors.New("missing secret field", "field", "endpoint")
+		return nil, kverrors.New("missing s3 secret field: endpoint", "field", "endpoint")
 	}
 	buckets, ok := s.Data["bucketnames"]
 	if !ok {
-		return nil, kverrors.New("missing secret field", "field", "bucketnames")
+		return nil, kverrors.New("missing s3 secret field: bucketnames", "field", "bucketnames")
+	}
+	// Buckets are provided as a comma-separated list
+	bucketNames := strings.Split(string(buckets), ",")
+	for i, name := range bucketNames {
+		bucketNames[i] = strings.TrimSpace(name)
+		if bucketNames[i] == "" {
+			return nil, kverrors.New("invalid s3 secret field: bucketnames", "field", "bucketnames")
+		}
+	}
+
+	// Extract and validate optional fields
+	region, ok := s.Data["region"]
+	if !ok {
+		region = []byte("")
 	}
-	// TODO buckets are comma-separated list
+
+	cfg := &storage.S3StorageConfig{
+		Endpoint: string(endpoint),
+		Buckets:  strings.Join(bucketNames, ","),
+		Region:   string(region),
+	}
+
+	// Short-lived credentials are obtained by assuming the IAM role
+	if sts != nil {
+		cfg.STS = &storage.STSConfig{
+			RoleARN:  sts.RoleARN,
+			Audience: sts.Audience,
+		}
+		if cfg.STS.Audience == "" {
+			cfg.STS.Audience = storage.DefaultSTSAudience
+		}
+		return cfg, nil
+	}
+
 	id, ok := s.Data["access_key_id"]
 	if !ok {
-		return nil, kverrors.New("missing secret field", "field", "access_key_id")
+		return nil, kverrors.New("missing s3 secret field: access_key_id", "field", "access_key_id")
 	}
 	secret, ok := s.Data["access_key_secret"]
 	if !ok {
-		return nil, kverrors.New("missing secret field", "field", "access_key_secret")
+		return nil, kverrors.New("missing s3 secret field: access_key_secret", "field", "access_key_secret")
+	}
+
+	cfg.AccessKeyID = string(id)
+	cfg.AccessKeySecret = string(secret)
+
+	return cfg, nil
+}
+
+func extractSwiftConfigSecret(s *corev1.Secret) (*storage.SwiftStorageConfig, error) {
+	// Extract and validate mandatory fields
+	url, ok := s.Data["auth_url"]
+	if !ok {
+		return nil, kverrors.New("missing swift secret field: auth_url", "field", "auth_url")
+	}
+	username, ok := s.Data["username"]
+	if !ok {
+		return nil, kverrors.New("missing swift secret field: username", "field", "username")
+	}
+	userDomainName, ok := s.Data["user_domain_name"]
+	if !ok {
+		return nil, kverrors.New("missing swift secret field: user_domain_name", "field", "user_domain_name")
+	}
+	password, ok := s.Data["password"]
+	if !ok {
+		return nil, kverrors.New("missing swift secret field: password", "field", "password")
+	}
+	container, ok := s.Data["container_name"]
+	if !ok {
+		return nil, kverrors.New("missing swift secret field: container_name", "field", "container_name")
 	}
 
 	// Extract and validate optional fields
+	projectName, ok := s.Data["project_name"]
+	if !ok {
+		projectName = []byte("")
+	}
+	projectDomainName, ok := s.Data["project_domain_name"]
+	if !ok {
+		projectDomainName = []byte("")
+	}
 	region, ok := s.Data["region"]
 	if !ok {
 		region = []byte("")
 	}
 
-	return &manifests.ObjectStorage{
-		Endpoint:        string(endpoint),
-		Buckets:         string(buckets),
-		AccessKeyID:     string(id),
-		AccessKeySecret: string(secret),
-		Region:          string(region),
+	return &storage.SwiftStorageConfig{
+		AuthURL:           string(url),
+		Username:          string(username),
+		UserDomainName:    string(userDomainName),
+		ProjectName:       string(projectName),
+		ProjectDomainName: string(projectDomainName),
+		Region:            string(region),
+		Container:         string(container),
+		Password:          string(password),
 	}, nil
 }
 
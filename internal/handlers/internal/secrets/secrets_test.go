@@ -3,12 +3,142 @@ package secrets_test
 import (
 	"testing"
 
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/handlers/internal/secrets"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 )
 
-func TestExtract(t *testing.T) {
+func TestAzureExtract(t *testing.T) {
+	type test struct {
+		name    string
+		secret  *corev1.Secret
+		wantErr bool
+	}
+	table := []test{
+		{
+			name:    "missing environment",
+			secret:  &corev1.Secret{},
+			wantErr: true,
+		},
+		{
+			name: "missing container",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"environment": []byte("AzureGlobal"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing account_name",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"environment": []byte("AzureGlobal"),
+					"container":   []byte("this,that"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing account_key",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"environment":  []byte("AzureGlobal"),
+					"container":    []byte("this,that"),
+					"account_name": []byte("id"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "all mandatory set",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"environment":  []byte("AzureGlobal"),
+					"container":    []byte("this,that"),
+					"account_name": []byte("id"),
+					"account_key":  []byte("secret"),
+				},
+			},
+		},
+		{
+			name: "all set including optional",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"environment":     []byte("AzureGlobal"),
+					"container":       []byte("this,that"),
+					"account_name":    []byte("id"),
+					"account_key":     []byte("secret"),
+					"endpoint_suffix": []byte("blob.core.windows.net"),
+				},
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := secrets.Extract(tst.secret, lokiv1beta1.ObjectStorageSecretAzure, nil)
+			if !tst.wantErr {
+				require.NoError(t, err)
+			}
+			if tst.wantErr {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestGCSExtract(t *testing.T) {
+	type test struct {
+		name    string
+		secret  *corev1.Secret
+		wantErr bool
+	}
+	table := []test{
+		{
+			name:    "missing bucketname",
+			secret:  &corev1.Secret{},
+			wantErr: true,
+		},
+		{
+			name: "missing key.json",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"bucketname": []byte("here"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "all set",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"bucketname": []byte("here"),
+					"key.json":   []byte("{\"type\": \"SA\"}"),
+				},
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := secrets.Extract(tst.secret, lokiv1beta1.ObjectStorageSecretGCS, nil)
+			if !tst.wantErr {
+				require.NoError(t, err)
+			}
+			if tst.wantErr {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestS3Extract(t *testing.T) {
 	type test struct {
 		name    string
 		secret  *corev1.Secret
@@ -67,7 +197,7 @@ func TestExtract(t *testing.T) {
 		t.Run(tst.name, func(t *testing.T) {
 			t.Parallel()
 
-			_, err := secrets.Extract(tst.secret)
+			_, err := secrets.Extract(tst.secret, lokiv1beta1.ObjectStorageSecretS3, nil)
 			if !tst.wantErr {
 				require.NoError(t, err)
 			}
@@ -78,6 +208,243 @@ func TestExtract(t *testing.T) {
 	}
 }
 
+func TestSwiftExtract(t *testing.T) {
+	type test struct {
+		name    string
+		secret  *corev1.Secret
+		wantErr bool
+	}
+	table := []test{
+		{
+			name:    "missing auth_url",
+			secret:  &corev1.Secret{},
+			wantErr: true,
+		},
+		{
+			name: "missing username",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"auth_url": []byte("here"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing user_domain_name",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"auth_url": []byte("here"),
+					"username": []byte("client"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing password",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"auth_url":         []byte("here"),
+					"username":         []byte("client"),
+					"user_domain_name": []byte("Default"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing container_name",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"auth_url":         []byte("here"),
+					"username":         []byte("client"),
+					"user_domain_name": []byte("Default"),
+					"password":         []byte("secret"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "all set",
+			secret: &corev1.Secret{
+				Data: map[string][]byte{
+					"auth_url":         []byte("here"),
+					"username":         []byte("client"),
+					"user_domain_name": []byte("Default"),
+					"password":         []byte("secret"),
+					"container_name":   []byte("loki"),
+				},
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := secrets.Extract(tst.secret, lokiv1beta1.ObjectStorageSecretSwift, nil)
+			if !tst.wantErr {
+				require.NoError(t, err)
+			}
+			if tst.wantErr {
+				require.NotNil(t, err)
+			}
+		})
+	}
+}
+
+func TestExtract_MissingFieldNamedInError(t *testing.T) {
+	s := &corev1.Secret{
+		Data: map[string][]byte{
+			"endpoint":    []byte("here"),
+			"bucketnames": []byte("this,that"),
+		},
+	}
+
+	_, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.EqualError(t, err, "missing s3 secret field: access_key_id")
+}
+
+func TestExtract_SecretHashChangesWithContents(t *testing.T) {
+	s := &corev1.Secret{
+		Data: map[string][]byte{
+			"endpoint":          []byte("here"),
+			"bucketnames":       []byte("this,that"),
+			"access_key_id":     []byte("id"),
+			"access_key_secret": []byte("secret"),
+		},
+	}
+
+	opts, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, opts.SecretSHA1)
+
+	s.Data["access_key_secret"] = []byte("rotated")
+
+	rotated, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, opts.SecretSHA1, rotated.SecretSHA1)
+}
+
+func TestExtract_SecretHashSeparatesKeysAndValues(t *testing.T) {
+	newSecret := func(key, value string) *corev1.Secret {
+		return &corev1.Secret{
+			Data: map[string][]byte{
+				"endpoint":          []byte("here"),
+				"bucketnames":       []byte("this,that"),
+				"access_key_id":     []byte("id"),
+				"access_key_secret": []byte("secret"),
+				key:                 []byte(value),
+			},
+		}
+	}
+
+	opts, err := secrets.Extract(newSecret("extra_ab", "c"), lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.NoError(t, err)
+
+	moved, err := secrets.Extract(newSecret("extra_a", "bc"), lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, opts.SecretSHA1, moved.SecretSHA1)
+}
+
+func TestExtract_ReadsProbeCredentials(t *testing.T) {
+	s := &corev1.Secret{
+		Data: map[string][]byte{
+			"endpoint":          []byte("here"),
+			"bucketnames":       []byte("this,that"),
+			"access_key_id":     []byte("id"),
+			"access_key_secret": []byte("secret"),
+		},
+	}
+
+	opts, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, nil)
+	require.NoError(t, err)
+	require.Equal(t, "id", opts.S3.AccessKeyID)
+	require.Equal(t, "secret", opts.S3.AccessKeySecret)
+}
+
+func TestS3Extract_BucketNames(t *testing.T) {
+	type test struct {
+		name    string
+		buckets string
+		want    string
+		wantErr bool
+	}
+	table := []test{
+		{
+			name:    "single bucket",
+			buckets: "loki",
+			want:    "loki",
+		},
+		{
+			name:    "multiple buckets",
+			buckets: "loki-1, loki-2 ,loki-3",
+			want:    "loki-1,loki-2,loki-3",
+		},
+		{
+			name:    "empty bucket name",
+			buckets: "loki-1,,loki-2",
+			wantErr: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &corev1.Secret{
+				Data: map[string][]byte{
+					"endpoint":          []byte("here"),
+					"bucketnames":       []byte(tst.buckets),
+					"access_key_id":     []byte("id"),
+					"access_key_secret": []byte("secret"),
+				},
+			}
+
+			opts, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, nil)
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tst.want, opts.S3.Buckets)
+		})
+	}
+}
+
+func TestS3Extract_WithSTS(t *testing.T) {
+	s := &corev1.Secret{
+		Data: map[string][]byte{
+			"endpoint":    []byte("here"),
+			"bucketnames": []byte("this,that"),
+		},
+	}
+	sts := &lokiv1beta1.ObjectStorageSTSSpec{
+		RoleARN: "arn:aws:iam::123456789012:role/loki",
+	}
+
+	opts, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretS3, sts)
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:iam::123456789012:role/loki", opts.S3.STS.RoleARN)
+	require.Equal(t, "sts.amazonaws.com", opts.S3.STS.Audience)
+
+	_, err = secrets.Extract(s, lokiv1beta1.ObjectStorageSecretGCS, sts)
+	require.Error(t, err)
+}
+
+func TestExtract_UnknownType(t *testing.T) {
+	s := &corev1.Secret{
+		Data: map[string][]byte{
+			"endpoint":          []byte("here"),
+			"bucketnames":       []byte("this,that"),
+			"access_key_id":     []byte("id"),
+			"access_key_secret": []byte("secret"),
+		},
+	}
+
+	_, err := secrets.Extract(s, lokiv1beta1.ObjectStorageSecretType("unknown"), nil)
+	require.Error(t, err)
+}
+
 func TestExtractGatewaySecret(t *testing.T) {
 	type test struct {
 		name       string
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	manifestsstorage "github.com/ViaQ/loki-operator/internal/manifests/storage"
+)
+
+const effectiveDateLayout = "2006-01-02"
+
+// BuildSchemaConfig validates the storage schemas of the given spec against the schemas
+// already applied to the LokiStack and returns them ordered by effective date.
+// The following rules apply:
+// - Effective dates must be valid and unique.
+// - The object store of a period must match the object storage secret type.
+// - Periods already in effect must not be changed or removed.
+// - New or changed periods must start after the current date in UTC.
+func BuildSchemaConfig(
+	utcTime time.Time,
+	spec lokiv1beta1.ObjectStorageSpec,
+	status lokiv1beta1.LokiStackStorageStatus,
+) ([]lokiv1beta1.ObjectStorageSchema, error) {
+	specSchemas := spec.Schemas
+	if len(specSchemas) == 0 {
+		specSchemas = []lokiv1beta1.ObjectStorageSchema{manifestsstorage.DefaultSchema(spec.Secret.Type)}
+	}
+
+	schemas := make([]lokiv1beta1.ObjectStorageSchema, 0, len(specSchemas))
+	dates := make(map[lokiv1beta1.StorageSchemaEffectiveDate]bool, len(specSchemas))
+	for _, s := range specSchemas {
+		if _, err := time.Parse(effectiveDateLayout, string(s.EffectiveDate)); err != nil {
+			return nil, kverrors.Wrap(err, "invalid storage schema effective date", "date", s.EffectiveDate)
+		}
+
+		if dates[s.EffectiveDate] {
+			return nil, kverrors.New("duplicate storage schema effective date", "date", s.EffectiveDate)
+		}
+		dates[s.EffectiveDate] = true
+
+		if s.IndexStore == "" {
+			s.IndexStore = lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper
+		}
+
+		if s.ObjectStore == "" {
+			s.ObjectStore = spec.Secret.Type
+		}
+
+		if s.ObjectStore != spec.Secret.Type {
+			return nil, kverrors.New("storage schema object store must match the object storage secret type",
+				"date", s.EffectiveDate,
+				"objectStore", s.ObjectStore,
+				"type", spec.Secret.Type,
+			)
+		}
+
+		schemas = append(schemas, s)
+	}
+
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].EffectiveDate < schemas[j].EffectiveDate
+	})
+
+	// Initial schema config, no periods have been applied yet.
+	if len(status.Schemas) == 0 {
+		return schemas, nil
+	}
+
+	today := lokiv1beta1.StorageSchemaEffectiveDate(utcTime.UTC().Format(effectiveDateLayout))
+
+	applied := make(map[lokiv1beta1.StorageSchemaEffectiveDate]lokiv1beta1.ObjectStorageSchema, len(status.Schemas))
+	for _, s := range status.Schemas {
+		applied[s.EffectiveDate] = s
+
+		if s.EffectiveDate > today {
+			continue
+		}
+
+		found := false
+		for _, ns := range schemas {
+			if ns == s {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return nil, kverrors.New("storage schema period in effect must not be changed or removed", "date", s.EffectiveDate)
+		}
+	}
+
+	for _, s := range schemas {
+		if as, ok := applied[s.EffectiveDate]; ok && as == s {
+			continue
+		}
+
+		if s.EffectiveDate <= today {
+			return nil, kverrors.New("new storage schema period must start after the current date", "date", s.EffectiveDate)
+		}
+	}
+
+	return schemas, nil
+}
@@ -0,0 +1,150 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSchemaConfig(t *testing.T) {
+	utcTime := time.Date(2021, time.November, 15, 12, 0, 0, 0, time.UTC)
+
+	secret := lokiv1beta1.ObjectStorageSecretSpec{
+		Type: lokiv1beta1.ObjectStorageSecretS3,
+		Name: "test",
+	}
+
+	v11 := lokiv1beta1.ObjectStorageSchema{
+		Version:       lokiv1beta1.ObjectStorageSchemaV11,
+		EffectiveDate: "2020-10-01",
+		IndexStore:    lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper,
+		ObjectStore:   lokiv1beta1.ObjectStorageSecretS3,
+	}
+	v12 := lokiv1beta1.ObjectStorageSchema{
+		Version:       lokiv1beta1.ObjectStorageSchemaV12,
+		EffectiveDate: "2021-11-16",
+		IndexStore:    lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper,
+		ObjectStore:   lokiv1beta1.ObjectStorageSecretS3,
+	}
+
+	type test struct {
+		name    string
+		spec    []lokiv1beta1.ObjectStorageSchema
+		status  []lokiv1beta1.ObjectStorageSchema
+		want    []lokiv1beta1.ObjectStorageSchema
+		wantErr bool
+	}
+	table := []test{
+		{
+			name: "no schemas defaults to v11",
+			want: []lokiv1beta1.ObjectStorageSchema{v11},
+		},
+		{
+			name: "initial schemas are ordered and defaulted",
+			spec: []lokiv1beta1.ObjectStorageSchema{
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV12,
+					EffectiveDate: "2021-11-16",
+				},
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV11,
+					EffectiveDate: "2020-10-01",
+				},
+			},
+			want: []lokiv1beta1.ObjectStorageSchema{v11, v12},
+		},
+		{
+			name: "invalid effective date",
+			spec: []lokiv1beta1.ObjectStorageSchema{
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV11,
+					EffectiveDate: "2020-13-01",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate effective date",
+			spec:    []lokiv1beta1.ObjectStorageSchema{v11, v11},
+			wantErr: true,
+		},
+		{
+			name: "object store not matching secret type",
+			spec: []lokiv1beta1.ObjectStorageSchema{
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV11,
+					EffectiveDate: "2020-10-01",
+					ObjectStore:   lokiv1beta1.ObjectStorageSecretGCS,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:   "new period in the future",
+			spec:   []lokiv1beta1.ObjectStorageSchema{v11, v12},
+			status: []lokiv1beta1.ObjectStorageSchema{v11},
+			want:   []lokiv1beta1.ObjectStorageSchema{v11, v12},
+		},
+		{
+			name: "new period starting today",
+			spec: []lokiv1beta1.ObjectStorageSchema{
+				v11,
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV12,
+					EffectiveDate: "2021-11-15",
+				},
+			},
+			status:  []lokiv1beta1.ObjectStorageSchema{v11},
+			wantErr: true,
+		},
+		{
+			name: "existing period changed",
+			spec: []lokiv1beta1.ObjectStorageSchema{
+				{
+					Version:       lokiv1beta1.ObjectStorageSchemaV12,
+					EffectiveDate: "2020-10-01",
+				},
+			},
+			status:  []lokiv1beta1.ObjectStorageSchema{v11},
+			wantErr: true,
+		},
+		{
+			name:    "existing period removed",
+			spec:    []lokiv1beta1.ObjectStorageSchema{v12},
+			status:  []lokiv1beta1.ObjectStorageSchema{v11},
+			wantErr: true,
+		},
+		{
+			name:   "future period removed",
+			spec:   []lokiv1beta1.ObjectStorageSchema{v11},
+			status: []lokiv1beta1.ObjectStorageSchema{v11, v12},
+			want:   []lokiv1beta1.ObjectStorageSchema{v11},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := lokiv1beta1.ObjectStorageSpec{
+				Secret:  secret,
+				Schemas: tst.spec,
+			}
+			status := lokiv1beta1.LokiStackStorageStatus{
+				Schemas: tst.status,
+			}
+
+			got, err := storage.BuildSchemaConfig(utcTime, spec, status)
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tst.want, got)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	manifestsstorage "github.com/ViaQ/loki-operator/internal/manifests/storage"
+)
+
+// BuildSSEConfig validates the server-side encryption settings of the given spec
+// and returns the S3 encryption config. It returns nil if encryption is not configured.
+// The following rules apply:
+// - Encryption is only supported for the object storage secret type s3.
+// - A KMS key ID and encryption context are only allowed with SSE-KMS.
+func BuildSSEConfig(spec lokiv1beta1.ObjectStorageSpec) (*manifestsstorage.SSEConfig, error) {
+	sse := spec.SSE
+	if sse == nil {
+		return nil, nil
+	}
+
+	if spec.Secret.Type != lokiv1beta1.ObjectStorageSecretS3 {
+		return nil, kverrors.New("server-side encryption is only supported for object storage secret type s3",
+			"type", spec.Secret.Type,
+		)
+	}
+
+	cfg := &manifestsstorage.SSEConfig{
+		Type: string(sse.Type),
+	}
+
+	switch sse.Type {
+	case lokiv1beta1.ObjectStorageSSES3:
+		if sse.KMSKeyID != "" {
+			return nil, kverrors.New("kms key id is only allowed with SSE-KMS", "type", sse.Type)
+		}
+		if len(sse.KMSEncryptionContext) > 0 {
+			return nil, kverrors.New("kms encryption context is only allowed with SSE-KMS", "type", sse.Type)
+		}
+	case lokiv1beta1.ObjectStorageSSEKMS:
+		cfg.KMSKeyID = sse.KMSKeyID
+
+		if len(sse.KMSEncryptionContext) > 0 {
+			ctx, err := json.Marshal(sse.KMSEncryptionContext)
+			if err != nil {
+				return nil, kverrors.Wrap(err, "failed to encode kms encryption context")
+			}
+			cfg.KMSEncryptionContext = string(ctx)
+		}
+	default:
+		return nil, kverrors.New("unknown server-side encryption type", "type", sse.Type)
+	}
+
+	return cfg, nil
+}
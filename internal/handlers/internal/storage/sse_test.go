@@ -0,0 +1,104 @@
+package storage_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/storage"
+	manifestsstorage "github.com/ViaQ/loki-operator/internal/manifests/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildSSEConfig(t *testing.T) {
+	type test struct {
+		name       string
+		secretType lokiv1beta1.ObjectStorageSecretType
+		sse        *lokiv1beta1.ObjectStorageSSESpec
+		want       *manifestsstorage.SSEConfig
+		wantErr    bool
+	}
+	table := []test{
+		{
+			name:       "no encryption",
+			secretType: lokiv1beta1.ObjectStorageSecretS3,
+		},
+		{
+			name:       "SSE-S3",
+			secretType: lokiv1beta1.ObjectStorageSecretS3,
+			sse: &lokiv1beta1.ObjectStorageSSESpec{
+				Type: lokiv1beta1.ObjectStorageSSES3,
+			},
+			want: &manifestsstorage.SSEConfig{
+				Type: "SSE-S3",
+			},
+		},
+		{
+			name:       "SSE-KMS with key id and encryption context",
+			secretType: lokiv1beta1.ObjectStorageSecretS3,
+			sse: &lokiv1beta1.ObjectStorageSSESpec{
+				Type:     lokiv1beta1.ObjectStorageSSEKMS,
+				KMSKeyID: "my-key",
+				KMSEncryptionContext: map[string]string{
+					"team": "logging",
+					"app":  "loki",
+				},
+			},
+			want: &manifestsstorage.SSEConfig{
+				Type:                 "SSE-KMS",
+				KMSKeyID:             "my-key",
+				KMSEncryptionContext: `{"app":"loki","team":"logging"}`,
+			},
+		},
+		{
+			name:       "SSE-S3 with key id",
+			secretType: lokiv1beta1.ObjectStorageSecretS3,
+			sse: &lokiv1beta1.ObjectStorageSSESpec{
+				Type:     lokiv1beta1.ObjectStorageSSES3,
+				KMSKeyID: "my-key",
+			},
+			wantErr: true,
+		},
+		{
+			name:       "SSE-S3 with encryption context",
+			secretType: lokiv1beta1.ObjectStorageSecretS3,
+			sse: &lokiv1beta1.ObjectStorageSSESpec{
+				Type: lokiv1beta1.ObjectStorageSSES3,
+				KMSEncryptionContext: map[string]string{
+					"app": "loki",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "unsupported secret type",
+			secretType: lokiv1beta1.ObjectStorageSecretGCS,
+			sse: &lokiv1beta1.ObjectStorageSSESpec{
+				Type: lokiv1beta1.ObjectStorageSSES3,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			spec := lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: tst.secretType,
+					Name: "test",
+				},
+				SSE: tst.sse,
+			}
+
+			got, err := storage.BuildSSEConfig(spec)
+			if tst.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tst.want, got)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// annotationDefaultStorageClass marks the storage class used for claims without a storage class name.
+const annotationDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
+
+// ExpansionNotSupportedError describes a volume claim template of an existing statefulset
+// requesting more storage from a storage class not allowing volume expansion.
+type ExpansionNotSupportedError struct {
+	StatefulSet  string
+	Claim        string
+	StorageClass string
+}
+
+// Error returns the error message including the statefulset, claim and storage class.
+func (e *ExpansionNotSupportedError) Error() string {
+	if e.StorageClass == "" {
+		return fmt.Sprintf("%s: volume claim %s cannot be expanded without a default storage class",
+			e.StatefulSet, e.Claim)
+	}
+	return fmt.Sprintf("%s: volume claim %s cannot be expanded, storage class %s does not allow volume expansion",
+		e.StatefulSet, e.Claim, e.StorageClass)
+}
+
+// ExpansionError describes a persistent volume claim which failed to be expanded.
+type ExpansionError struct {
+	Claim string
+	Err   error
+}
+
+// Error returns the error message including the claim and the cause.
+func (e *ExpansionError) Error() string {
+	return fmt.Sprintf("failed to expand volume claim %s: %s", e.Claim, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ExpansionError) Unwrap() error {
+	return e.Err
+}
+
+// ExpandPVCs grows the persistent volume claims of existing statefulsets whose desired
+// volume claim templates request more storage. Volume claim templates are immutable,
+// so each such statefulset is deleted orphaning its pods and claims, to be recreated
+// with the new templates afterwards. The pods keep running in the meantime.
+func ExpandPVCs(ctx context.Context, k k8s.Client, namespace string, objs []client.Object) error {
+	for _, obj := range objs {
+		desired, ok := obj.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+
+		var existing appsv1.StatefulSet
+		key := client.ObjectKey{Name: desired.Name, Namespace: namespace}
+		if err := k.Get(ctx, key, &existing); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return kverrors.Wrap(err, "failed to lookup statefulset", "name", key)
+		}
+
+		current := map[string]corev1.PersistentVolumeClaim{}
+		for _, pvc := range existing.Spec.VolumeClaimTemplates {
+			current[pvc.Name] = pvc
+		}
+
+		var expanded bool
+		for _, pvc := range desired.Spec.VolumeClaimTemplates {
+			tpl, ok := current[pvc.Name]
+			if !ok {
+				continue
+			}
+
+			size := tpl.Spec.Resources.Requests[corev1.ResourceStorage]
+			want := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if want.Cmp(size) <= 0 {
+				continue
+			}
+
+			if err := checkExpansionAllowed(ctx, k, existing.Name, tpl); err != nil {
+				return err
+			}
+
+			if err := expandClaims(ctx, k, namespace, &existing, tpl, want); err != nil {
+				return err
+			}
+			expanded = true
+		}
+
+		if !expanded {
+			continue
+		}
+
+		if err := k.Delete(ctx, &existing, client.PropagationPolicy(metav1.DeletePropagationOrphan)); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return kverrors.Wrap(err, "failed to delete statefulset for recreation", "name", key)
+		}
+	}
+
+	return nil
+}
+
+func checkExpansionAllowed(ctx context.Context, k k8s.Client, sts string, tpl corev1.PersistentVolumeClaim) error {
+	var sc *storagev1.StorageClass
+
+	if name := tpl.Spec.StorageClassName; name != nil && *name != "" {
+		sc = &storagev1.StorageClass{}
+		if err := k.Get(ctx, client.ObjectKey{Name: *name}, sc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return &ExpansionNotSupportedError{StatefulSet: sts, Claim: tpl.Name, StorageClass: *name}
+			}
+			return kverrors.Wrap(err, "failed to lookup storage class", "name", *name)
+		}
+	} else {
+		var err error
+		sc, err = defaultStorageClass(ctx, k)
+		if err != nil {
+			return err
+		}
+		if sc == nil {
+			return &ExpansionNotSupportedError{StatefulSet: sts, Claim: tpl.Name}
+		}
+	}
+
+	if sc.AllowVolumeExpansion == nil || !*sc.AllowVolumeExpansion {
+		return &ExpansionNotSupportedError{StatefulSet: sts, Claim: tpl.Name, StorageClass: sc.Name}
+	}
+
+	return nil
+}
+
+func defaultStorageClass(ctx context.Context, k k8s.Client) (*storagev1.StorageClass, error) {
+	var scs storagev1.StorageClassList
+	if err := k.List(ctx, &scs); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list storage classes")
+	}
+
+	for i, sc := range scs.Items {
+		if sc.Annotations[annotationDefaultStorageClass] == "true" {
+			return &scs.Items[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// expandClaims patches the storage request of all claims created from the template
+// of the given statefulset. Claims carry the template labels and are named
+// <template>-<statefulset>-<ordinal>.
+func expandClaims(ctx context.Context, k k8s.Client, namespace string, sts *appsv1.StatefulSet, tpl corev1.PersistentVolumeClaim, size resource.Quantity) error {
+	var pvcs corev1.PersistentVolumeClaimList
+	opts := []client.ListOption{
+		client.InNamespace(namespace),
+		client.MatchingLabels(tpl.Labels),
+	}
+	if err := k.List(ctx, &pvcs, opts...); err != nil {
+		return kverrors.Wrap(err, "failed to list persistent volume claims", "statefulset", sts.Name)
+	}
+
+	prefix := fmt.Sprintf("%s-%s-", tpl.Name, sts.Name)
+	for i := range pvcs.Items {
+		pvc := &pvcs.Items[i]
+		if !strings.HasPrefix(pvc.Name, prefix) {
+			continue
+		}
+
+		current := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if current.Cmp(size) >= 0 {
+			continue
+		}
+
+		patch := client.MergeFrom(pvc.DeepCopy())
+		if pvc.Spec.Resources.Requests == nil {
+			pvc.Spec.Resources.Requests = corev1.ResourceList{}
+		}
+		pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+
+		if err := k.Patch(ctx, pvc, patch); err != nil {
+			return &ExpansionError{Claim: pvc.Name, Err: err}
+		}
+	}
+
+	return nil
+}
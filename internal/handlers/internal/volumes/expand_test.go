@@ -0,0 +1,231 @@
+package volumes_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/volumes"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newClaim(name, size string) corev1.PersistentVolumeClaim {
+	return corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "some-ns",
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+func newStorageClass(name string, allowExpansion, isDefault bool) storagev1.StorageClass {
+	sc := storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		AllowVolumeExpansion: pointer.BoolPtr(allowExpansion),
+	}
+	if isDefault {
+		sc.Annotations = map[string]string{
+			"storageclass.kubernetes.io/is-default-class": "true",
+		}
+	}
+	return sc
+}
+
+func TestExpandPVCs(t *testing.T) {
+	withStorageClass := func(sts *appsv1.StatefulSet, name string) *appsv1.StatefulSet {
+		sts.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = pointer.StringPtr(name)
+		return sts
+	}
+
+	type test struct {
+		name           string
+		existing       *appsv1.StatefulSet
+		desired        *appsv1.StatefulSet
+		storageClasses []storagev1.StorageClass
+		wantPatched    []string
+		wantDeleted    bool
+		wantErr        string
+	}
+	table := []test{
+		{
+			name:    "new statefulset",
+			desired: newStatefulSet("10Gi"),
+		},
+		{
+			name:     "same size",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("10Gi"),
+		},
+		{
+			name:           "growing with expandable storage class",
+			existing:       withStorageClass(newStatefulSet("10Gi"), "gp2"),
+			desired:        withStorageClass(newStatefulSet("20Gi"), "gp2"),
+			storageClasses: []storagev1.StorageClass{newStorageClass("gp2", true, false)},
+			wantPatched:    []string{"storage-loki-ingester-my-stack-0", "storage-loki-ingester-my-stack-1"},
+			wantDeleted:    true,
+		},
+		{
+			name:     "growing with expandable default storage class",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("20Gi"),
+			storageClasses: []storagev1.StorageClass{
+				newStorageClass("standard", false, false),
+				newStorageClass("gp2", true, true),
+			},
+			wantPatched: []string{"storage-loki-ingester-my-stack-0", "storage-loki-ingester-my-stack-1"},
+			wantDeleted: true,
+		},
+		{
+			name:           "growing with non-expandable storage class",
+			existing:       withStorageClass(newStatefulSet("10Gi"), "standard"),
+			desired:        withStorageClass(newStatefulSet("20Gi"), "standard"),
+			storageClasses: []storagev1.StorageClass{newStorageClass("standard", false, false)},
+			wantErr:        "loki-ingester-my-stack: volume claim storage cannot be expanded, storage class standard does not allow volume expansion",
+		},
+		{
+			name:     "growing without default storage class",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("20Gi"),
+			wantErr:  "loki-ingester-my-stack: volume claim storage cannot be expanded without a default storage class",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			claims := &corev1.PersistentVolumeClaimList{
+				Items: []corev1.PersistentVolumeClaim{
+					newClaim("storage-loki-ingester-my-stack-0", "10Gi"),
+					newClaim("storage-loki-ingester-my-stack-1", "10Gi"),
+					newClaim("wal-loki-ingester-my-stack-0", "10Gi"),
+				},
+			}
+
+			k := &k8sfakes.FakeClient{}
+			k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+				switch object.(type) {
+				case *appsv1.StatefulSet:
+					if tst.existing != nil {
+						k.SetClientObject(object, tst.existing)
+						return nil
+					}
+				case *storagev1.StorageClass:
+					for _, sc := range tst.storageClasses {
+						if sc.Name == name.Name {
+							sc := sc
+							k.SetClientObject(object, &sc)
+							return nil
+						}
+					}
+				}
+				return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+			}
+			k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+				switch list.(type) {
+				case *storagev1.StorageClassList:
+					k.SetClientObjectList(list, &storagev1.StorageClassList{Items: tst.storageClasses})
+				case *corev1.PersistentVolumeClaimList:
+					k.SetClientObjectList(list, claims)
+				}
+				return nil
+			}
+
+			var patched []string
+			k.PatchStub = func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+				pvc := obj.(*corev1.PersistentVolumeClaim)
+				require.Equal(t, resource.MustParse("20Gi"), pvc.Spec.Resources.Requests[corev1.ResourceStorage])
+				patched = append(patched, pvc.Name)
+				return nil
+			}
+
+			objs := []client.Object{
+				&corev1.ConfigMap{},
+				tst.desired,
+			}
+
+			err := volumes.ExpandPVCs(context.TODO(), k, "some-ns", objs)
+			if tst.wantErr != "" {
+				var notSupportedErr *volumes.ExpansionNotSupportedError
+				require.True(t, errors.As(err, &notSupportedErr))
+				require.EqualError(t, err, tst.wantErr)
+				require.Zero(t, k.PatchCallCount())
+				require.Zero(t, k.DeleteCallCount())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tst.wantPatched, patched)
+
+			if !tst.wantDeleted {
+				require.Zero(t, k.DeleteCallCount())
+				return
+			}
+
+			require.Equal(t, 1, k.DeleteCallCount())
+			_, obj, opts := k.DeleteArgsForCall(0)
+			require.Equal(t, tst.existing.Name, obj.GetName())
+			require.Contains(t, opts, client.PropagationPolicy(metav1.DeletePropagationOrphan))
+		})
+	}
+}
+
+func TestExpandPVCs_WhenPatchFails_ReturnsExpansionError(t *testing.T) {
+	existing := newStatefulSet("10Gi")
+	existing.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = pointer.StringPtr("gp2")
+
+	k := &k8sfakes.FakeClient{}
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		switch object.(type) {
+		case *appsv1.StatefulSet:
+			k.SetClientObject(object, existing)
+		case *storagev1.StorageClass:
+			sc := newStorageClass(name.Name, true, false)
+			k.SetClientObject(object, &sc)
+		}
+		return nil
+	}
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		k.SetClientObjectList(list, &corev1.PersistentVolumeClaimList{
+			Items: []corev1.PersistentVolumeClaim{
+				newClaim("storage-loki-ingester-my-stack-0", "10Gi"),
+			},
+		})
+		return nil
+	}
+	k.PatchStub = func(_ context.Context, _ client.Object, _ client.Patch, _ ...client.PatchOption) error {
+		return apierrors.NewForbidden(schema.GroupResource{}, "storage-loki-ingester-my-stack-0", errors.New("quota exceeded"))
+	}
+
+	desired := newStatefulSet("20Gi")
+	desired.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = pointer.StringPtr("gp2")
+
+	err := volumes.ExpandPVCs(context.TODO(), k, "some-ns", []client.Object{desired})
+	require.Error(t, err)
+
+	var expansionErr *volumes.ExpansionError
+	require.True(t, errors.As(err, &expansionErr))
+	require.Equal(t, "storage-loki-ingester-my-stack-0", expansionErr.Claim)
+	require.Zero(t, k.DeleteCallCount())
+}
@@ -0,0 +1,94 @@
+package volumes
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ShrinkError describes a volume claim template of an existing statefulset
+// requesting less storage than before.
+type ShrinkError struct {
+	StatefulSet string
+	Claim       string
+	Current     resource.Quantity
+	Desired     resource.Quantity
+}
+
+// Error returns the error message including the statefulset, claim and sizes.
+func (e *ShrinkError) Error() string {
+	return fmt.Sprintf("%s: volume claim %s cannot shrink from %s to %s",
+		e.StatefulSet, e.Claim, e.Current.String(), e.Desired.String())
+}
+
+// ValidatePVCSizes checks that the volume claim templates of the desired statefulsets
+// do not request less storage than the ones of the existing statefulsets.
+func ValidatePVCSizes(ctx context.Context, k k8s.Client, namespace string, objs []client.Object) error {
+	for _, obj := range objs {
+		desired, ok := obj.(*appsv1.StatefulSet)
+		if !ok {
+			continue
+		}
+
+		var existing appsv1.StatefulSet
+		key := client.ObjectKey{Name: desired.Name, Namespace: namespace}
+		if err := k.Get(ctx, key, &existing); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return kverrors.Wrap(err, "failed to lookup statefulset", "name", key)
+		}
+
+		current := map[string]resource.Quantity{}
+		for _, pvc := range existing.Spec.VolumeClaimTemplates {
+			current[pvc.Name] = pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		}
+
+		for _, pvc := range desired.Spec.VolumeClaimTemplates {
+			size, ok := current[pvc.Name]
+			if !ok {
+				continue
+			}
+
+			want := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+			if want.Cmp(size) < 0 {
+				return &ShrinkError{
+					StatefulSet: desired.Name,
+					Claim:       pvc.Name,
+					Current:     size,
+					Desired:     want,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// ClaimSize returns the storage requested by the named volume claim template of an
+// existing statefulset. It returns a zero quantity if the statefulset or claim does not exist.
+func ClaimSize(ctx context.Context, k k8s.Client, key client.ObjectKey, claim string) (resource.Quantity, error) {
+	var sts appsv1.StatefulSet
+	if err := k.Get(ctx, key, &sts); err != nil {
+		if apierrors.IsNotFound(err) {
+			return resource.Quantity{}, nil
+		}
+		return resource.Quantity{}, kverrors.Wrap(err, "failed to lookup statefulset", "name", key)
+	}
+
+	for _, pvc := range sts.Spec.VolumeClaimTemplates {
+		if pvc.Name == claim {
+			return pvc.Spec.Resources.Requests[corev1.ResourceStorage], nil
+		}
+	}
+
+	return resource.Quantity{}, nil
+}
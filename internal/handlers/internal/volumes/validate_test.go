@@ -0,0 +1,164 @@
+package volumes_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/volumes"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newStatefulSet(size string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "loki-ingester-my-stack",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "storage",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse(size),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidatePVCSizes(t *testing.T) {
+	type test struct {
+		name     string
+		existing *appsv1.StatefulSet
+		desired  *appsv1.StatefulSet
+		wantErr  string
+	}
+	table := []test{
+		{
+			name:    "new statefulset",
+			desired: newStatefulSet("10Gi"),
+		},
+		{
+			name:     "same size",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("10Gi"),
+		},
+		{
+			name:     "growing",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("20Gi"),
+		},
+		{
+			name:     "shrinking",
+			existing: newStatefulSet("10Gi"),
+			desired:  newStatefulSet("5Gi"),
+			wantErr:  "loki-ingester-my-stack: volume claim storage cannot shrink from 10Gi to 5Gi",
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			k := &k8sfakes.FakeClient{}
+			k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+				if tst.existing == nil {
+					return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+				}
+				k.SetClientObject(object, tst.existing)
+				return nil
+			}
+
+			objs := []client.Object{
+				&corev1.ConfigMap{},
+				tst.desired,
+			}
+
+			err := volumes.ValidatePVCSizes(context.TODO(), k, "some-ns", objs)
+			if tst.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var shrinkErr *volumes.ShrinkError
+			require.True(t, errors.As(err, &shrinkErr))
+			require.EqualError(t, err, tst.wantErr)
+		})
+	}
+}
+
+func TestValidatePVCSizes_WhenGetFails_ReturnsError(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+	k.GetStub = func(_ context.Context, _ types.NamespacedName, _ client.Object) error {
+		return apierrors.NewBadRequest("bad request")
+	}
+
+	err := volumes.ValidatePVCSizes(context.TODO(), k, "some-ns", []client.Object{newStatefulSet("1Gi")})
+	require.Error(t, err)
+
+	var shrinkErr *volumes.ShrinkError
+	require.False(t, errors.As(err, &shrinkErr))
+}
+
+func TestClaimSize(t *testing.T) {
+	type test struct {
+		name     string
+		existing *appsv1.StatefulSet
+		claim    string
+		want     resource.Quantity
+	}
+	table := []test{
+		{
+			name:  "statefulset not found",
+			claim: "storage",
+		},
+		{
+			name:     "claim not found",
+			existing: newStatefulSet("10Gi"),
+			claim:    "wal",
+		},
+		{
+			name:     "claim found",
+			existing: newStatefulSet("10Gi"),
+			claim:    "storage",
+			want:     resource.MustParse("10Gi"),
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			k := &k8sfakes.FakeClient{}
+			k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+				if tst.existing == nil {
+					return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+				}
+				k.SetClientObject(object, tst.existing)
+				return nil
+			}
+
+			key := client.ObjectKey{Name: "loki-ingester-my-stack", Namespace: "some-ns"}
+			size, err := volumes.ClaimSize(context.TODO(), k, key, tst.claim)
+			require.NoError(t, err)
+			require.Equal(t, 0, tst.want.Cmp(size))
+		})
+	}
+}
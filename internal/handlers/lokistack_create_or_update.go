@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/openshift"
 
@@ -11,9 +13,16 @@ import (
 	"github.com/ViaQ/logerr/log"
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/autoscaling"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/caches"
 	"github.com/ViaQ/loki-operator/internal/handlers/internal/gateway"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/rules"
 	"github.com/ViaQ/loki-operator/internal/handlers/internal/secrets"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/storage"
+	"github.com/ViaQ/loki-operator/internal/handlers/internal/volumes"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	manifestsstorage "github.com/ViaQ/loki-operator/internal/manifests/storage"
 	"github.com/ViaQ/loki-operator/internal/metrics"
 	"github.com/ViaQ/loki-operator/internal/status"
 
@@ -21,12 +30,22 @@ import (
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 // CreateOrUpdateLokiStack handles LokiStack create and update events.
-func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client, s *runtime.Scheme, flags manifests.FeatureFlags) error {
+func CreateOrUpdateLokiStack(
+	ctx context.Context,
+	req ctrl.Request,
+	k k8s.Client,
+	rec record.EventRecorder,
+	s *runtime.Scheme,
+	p objectstorage.Prober,
+	flags manifests.FeatureFlags,
+) error {
 	ll := log.WithValues("lokistack", req.NamespacedName, "event", "createOrUpdate")
 
 	var stack lokiv1beta1.LokiStack
@@ -49,26 +68,117 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 		gwImg = manifests.DefaultLokiStackGatewayImage
 	}
 
-	var s3secret corev1.Secret
+	memcachedImg := os.Getenv(manifests.EnvRelatedImageMemcached)
+	if memcachedImg == "" {
+		memcachedImg = manifests.DefaultMemcachedImage
+	}
+
+	var storageSecret corev1.Secret
 	key := client.ObjectKey{Name: stack.Spec.Storage.Secret.Name, Namespace: stack.Namespace}
-	if err := k.Get(ctx, key, &s3secret); err != nil {
+	if err := k.Get(ctx, key, &storageSecret); err != nil {
 		if apierrors.IsNotFound(err) {
-			return status.SetDegradedCondition(ctx, k, req,
+			return status.SetDegradedCondition(ctx, k, rec, req,
 				"Missing object storage secret",
 				lokiv1beta1.ReasonMissingObjectStorageSecret,
 			)
 		}
-		return kverrors.Wrap(err, "failed to lookup lokistack s3 secret", "name", key)
+		return kverrors.Wrap(err, "failed to lookup lokistack storage secret", "name", key)
 	}
 
-	storage, err := secrets.Extract(&s3secret)
+	objStore, err := secrets.Extract(&storageSecret, stack.Spec.Storage.Secret.Type, stack.Spec.Storage.STS)
 	if err != nil {
-		return status.SetDegradedCondition(ctx, k, req,
-			"Invalid object storage secret contents",
+		return status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Invalid object storage secret contents: %s", err),
 			lokiv1beta1.ReasonInvalidObjectStorageSecret,
 		)
 	}
 
+	if tls := stack.Spec.Storage.TLS; tls != nil {
+		objStore.TLS = &manifestsstorage.TLSConfig{
+			CA:                 tls.CA,
+			CAKey:              tls.CAKey,
+			InsecureSkipVerify: tls.InsecureSkipVerify,
+		}
+
+		if objStore.TLS.CAKey == "" {
+			objStore.TLS.CAKey = manifestsstorage.DefaultCAKey
+		}
+
+		if tls.CA != "" {
+			var cm corev1.ConfigMap
+			key := client.ObjectKey{Name: tls.CA, Namespace: stack.Namespace}
+			if err = k.Get(ctx, key, &cm); err != nil {
+				if apierrors.IsNotFound(err) {
+					return status.SetDegradedCondition(ctx, k, rec, req,
+						"Missing object storage CA config map",
+						lokiv1beta1.ReasonMissingObjectStorageCAConfigMap,
+					)
+				}
+				return kverrors.Wrap(err, "failed to lookup lokistack object storage CA config map", "name", key)
+			}
+
+			ca, ok := cm.Data[objStore.TLS.CAKey]
+			if !ok {
+				return status.SetDegradedCondition(ctx, k, rec, req,
+					fmt.Sprintf("Invalid object storage CA config map contents: missing key %s", objStore.TLS.CAKey),
+					lokiv1beta1.ReasonInvalidObjectStorageCAConfigMap,
+				)
+			}
+			objStore.TLS.CABundle = ca
+		}
+	}
+
+	sse, err := storage.BuildSSEConfig(stack.Spec.Storage)
+	if err != nil {
+		return status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Invalid object storage encryption config: %s", err),
+			lokiv1beta1.ReasonInvalidObjectStorageEncryption,
+		)
+	}
+	if sse != nil {
+		objStore.S3.SSE = sse
+	}
+
+	objStore.Schemas, err = storage.BuildSchemaConfig(time.Now().UTC(), stack.Spec.Storage, stack.Status.Storage)
+	if err != nil {
+		return status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Invalid object storage schema contents: %s", err),
+			lokiv1beta1.ReasonInvalidObjectStorageSchema,
+		)
+	}
+
+	var (
+		alertingRules  []lokiv1beta1.AlertingRule
+		recordingRules []lokiv1beta1.RecordingRule
+	)
+	if stack.Spec.Rules != nil && stack.Spec.Rules.Enabled {
+		alertingRules, recordingRules, err = rules.List(ctx, k, req.Namespace, stack.Spec.Rules)
+		if err != nil {
+			return err
+		}
+
+		if err = rules.Validate(alertingRules, recordingRules); err != nil {
+			return status.SetDegradedCondition(ctx, k, rec, req,
+				fmt.Sprintf("Invalid rules configuration: %s", err),
+				lokiv1beta1.ReasonInvalidRulesConfiguration,
+			)
+		}
+	}
+
+	if err = caches.Validate(stack.Spec.Caches); err != nil {
+		return status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Invalid cache configuration: %s", err),
+			lokiv1beta1.ReasonInvalidCacheConfiguration,
+		)
+	}
+
+	if err = autoscaling.Validate(stack.Spec.Template); err != nil {
+		return status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Invalid autoscaling configuration: %s", err),
+			lokiv1beta1.ReasonInvalidAutoscalingConfiguration,
+		)
+	}
+
 	var (
 		baseDomain      string
 		tenantSecrets   []*manifests.TenantSecrets
@@ -76,21 +186,21 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 	)
 	if flags.EnableGateway && stack.Spec.Tenants != nil {
 		if err = gateway.ValidateModes(stack); err != nil {
-			return status.SetDegradedCondition(ctx, k, req,
+			return status.SetDegradedCondition(ctx, k, rec, req,
 				fmt.Sprintf("Invalid tenants configuration: %s", err),
 				lokiv1beta1.ReasonInvalidTenantsConfiguration,
 			)
 		}
 
 		if stack.Spec.Tenants.Mode != lokiv1beta1.OpenshiftLogging {
-			tenantSecrets, err = gateway.GetTenantSecrets(ctx, k, req, &stack)
+			tenantSecrets, err = gateway.GetTenantSecrets(ctx, k, rec, req, &stack)
 			if err != nil {
 				return err
 			}
 		}
 
 		if stack.Spec.Tenants.Mode == lokiv1beta1.OpenshiftLogging {
-			baseDomain, err = gateway.GetOpenShiftBaseDomain(ctx, k, req)
+			baseDomain, err = gateway.GetOpenShiftBaseDomain(ctx, k, rec, req)
 			if err != nil {
 				return nil
 			}
@@ -100,18 +210,31 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 		}
 	}
 
+	// The ingester volumes are larger with the write-ahead log enabled and must
+	// not shrink if it is disabled again.
+	ingesterKey := client.ObjectKey{Name: manifests.IngesterName(req.Name), Namespace: req.Namespace}
+	ingesterPVCSize, err := volumes.ClaimSize(ctx, k, ingesterKey, manifests.StorageVolumeClaimName)
+	if err != nil {
+		return err
+	}
+
 	// Here we will translate the lokiv1beta1.LokiStack options into manifest options
 	opts := manifests.Options{
 		Name:              req.Name,
 		Namespace:         req.Namespace,
 		Image:             img,
 		GatewayImage:      gwImg,
+		MemcachedImage:    memcachedImg,
 		GatewayBaseDomain: baseDomain,
 		Stack:             stack.Spec,
 		Flags:             flags,
-		ObjectStorage:     *storage,
+		ObjectStorage:     *objStore,
 		TenantSecrets:     tenantSecrets,
 		TenantConfigMap:   tenantConfigMap,
+		AlertingRules:     alertingRules,
+		RecordingRules:    recordingRules,
+
+		DeployedIngesterPVCSize: ingesterPVCSize,
 	}
 
 	ll.Info("begin building manifests")
@@ -135,6 +258,53 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 	}
 	ll.Info("manifests built", "count", len(objects))
 
+	if err = volumes.ValidatePVCSizes(ctx, k, req.Namespace, objects); err != nil {
+		var shrinkErr *volumes.ShrinkError
+		if errors.As(err, &shrinkErr) {
+			return status.SetDegradedCondition(ctx, k, rec, req,
+				fmt.Sprintf("Invalid PVC size: %s", err),
+				lokiv1beta1.ReasonPVCShrinkNotSupported,
+			)
+		}
+		return err
+	}
+
+	// Check the object storage before rolling out components, which would
+	// otherwise crash-loop on a bad endpoint or bucket.
+	if err = p.Probe(ctx, opts.ObjectStorage); err != nil {
+		statusErr := status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Object storage unreachable: %s", err),
+			lokiv1beta1.ReasonObjectStorageUnreachable,
+		)
+		if statusErr != nil {
+			return statusErr
+		}
+
+		// Requeue with backoff, the object storage may be unavailable only temporarily.
+		return kverrors.Wrap(err, "failed to probe object storage", "name", req.NamespacedName)
+	}
+
+	// Grow the claims of existing statefulsets last, because their
+	// recreation must follow right after the orphan deletion.
+	if err = volumes.ExpandPVCs(ctx, k, req.Namespace, objects); err != nil {
+		var notSupportedErr *volumes.ExpansionNotSupportedError
+		if errors.As(err, &notSupportedErr) {
+			return status.SetDegradedCondition(ctx, k, rec, req,
+				fmt.Sprintf("Invalid PVC size: %s", err),
+				lokiv1beta1.ReasonVolumeExpansionNotSupported,
+			)
+		}
+
+		var expansionErr *volumes.ExpansionError
+		if errors.As(err, &expansionErr) {
+			return status.SetDegradedCondition(ctx, k, rec, req,
+				fmt.Sprintf("PVC expansion failed: %s", err),
+				lokiv1beta1.ReasonVolumeExpansionFailed,
+			)
+		}
+		return err
+	}
+
 	var errCount int32
 
 	for _, obj := range objects {
@@ -143,11 +313,16 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 			"object_kind", obj.GetObjectKind(),
 		)
 
+		// Keep the kind, the object is overwritten with the API server response.
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+
 		if isNamespaceScoped(obj) {
 			obj.SetNamespace(req.Namespace)
 
 			if err := ctrl.SetControllerReference(&stack, obj, s); err != nil {
 				l.Error(err, "failed to set controller owner reference to resource")
+				rec.Eventf(&stack, corev1.EventTypeWarning, string(lokiv1beta1.ReasonFailedComponents),
+					"Failed to set owner reference on %s %s: %s", kind, obj.GetName(), err)
 				errCount++
 				continue
 			}
@@ -159,17 +334,59 @@ func CreateOrUpdateLokiStack(ctx context.Context, req ctrl.Request, k k8s.Client
 		op, err := ctrl.CreateOrUpdate(ctx, k, obj, mutateFn)
 		if err != nil {
 			l.Error(err, "failed to configure resource")
+			rec.Eventf(&stack, corev1.EventTypeWarning, string(lokiv1beta1.ReasonFailedComponents),
+				"Failed to configure %s %s: %s", kind, obj.GetName(), err)
 			errCount++
 			continue
 		}
 
 		l.Info(fmt.Sprintf("Resource has been %s", op))
+
+		switch op {
+		case controllerutil.OperationResultCreated:
+			rec.Eventf(&stack, corev1.EventTypeNormal, string(lokiv1beta1.ReasonCreatedComponents),
+				"Created %s %s", kind, obj.GetName())
+		case controllerutil.OperationResultUpdated:
+			rec.Eventf(&stack, corev1.EventTypeNormal, string(lokiv1beta1.ReasonUpdatedComponents),
+				"Updated %s %s", kind, obj.GetName())
+		}
+	}
+
+	for _, obj := range manifests.BuildObsolete(opts) {
+		l := ll.WithValues(
+			"object_name", obj.GetName(),
+			"object_kind", obj.GetObjectKind(),
+		)
+
+		kind := obj.GetObjectKind().GroupVersionKind().Kind
+		obj.SetNamespace(req.Namespace)
+
+		if err := k.Delete(ctx, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+
+			l.Error(err, "failed to delete resource")
+			rec.Eventf(&stack, corev1.EventTypeWarning, string(lokiv1beta1.ReasonFailedComponents),
+				"Failed to delete %s %s: %s", kind, obj.GetName(), err)
+			errCount++
+			continue
+		}
+
+		l.Info("Resource has been deleted")
+		rec.Eventf(&stack, corev1.EventTypeNormal, string(lokiv1beta1.ReasonDeletedComponents),
+			"Deleted %s %s", kind, obj.GetName())
 	}
 
 	if errCount > 0 {
 		return kverrors.New("failed to configure lokistack resources", "name", req.NamespacedName)
 	}
 
+	if err = status.SetStorageSchemaStatus(ctx, k, req, objStore.Schemas); err != nil {
+		ll.Error(err, "failed to set storage schema status")
+		return err
+	}
+
 	// 1x.extra-small is used only for development, so the metrics will not
 	// be collected.
 	if opts.Stack.Size != lokiv1beta1.SizeOneXExtraSmall {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"flag"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
@@ -11,14 +12,20 @@ import (
 	"github.com/ViaQ/logerr/log"
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage/objectstoragefakes"
 	"github.com/ViaQ/loki-operator/internal/handlers"
 	"github.com/ViaQ/loki-operator/internal/manifests"
 	routev1 "github.com/openshift/api/route/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -27,6 +34,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -111,7 +119,7 @@ func TestCreateOrUpdateLokiStack_WhenGetReturnsNotFound_DoesNotError(t *testing.
 		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 	require.NoError(t, err)
 
 	// make sure create was NOT called because the Get failed
@@ -132,7 +140,7 @@ func TestCreateOrUpdateLokiStack_WhenGetReturnsAnErrorOtherThanNotFound_ReturnsT
 		return badRequestErr
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	require.Equal(t, badRequestErr, errors.Unwrap(err))
 
@@ -141,6 +149,7 @@ func TestCreateOrUpdateLokiStack_WhenGetReturnsAnErrorOtherThanNotFound_ReturnsT
 }
 
 func TestCreateOrUpdateLokiStack_SetsNamespaceOnAllObjects(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
 	k := &k8sfakes.FakeClient{}
 	r := ctrl.Request{
 		NamespacedName: types.NamespacedName{
@@ -162,6 +171,7 @@ func TestCreateOrUpdateLokiStack_SetsNamespaceOnAllObjects(t *testing.T) {
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -208,7 +218,9 @@ func TestCreateOrUpdateLokiStack_SetsNamespaceOnAllObjects(t *testing.T) {
 		return nil
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 	require.NoError(t, err)
 
 	// make sure create was called
@@ -216,6 +228,7 @@ func TestCreateOrUpdateLokiStack_SetsNamespaceOnAllObjects(t *testing.T) {
 }
 
 func TestCreateOrUpdateLokiStack_SetsOwnerRefOnAllObjects(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
 	k := &k8sfakes.FakeClient{}
 	r := ctrl.Request{
 		NamespacedName: types.NamespacedName{
@@ -237,6 +250,7 @@ func TestCreateOrUpdateLokiStack_SetsOwnerRefOnAllObjects(t *testing.T) {
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -305,7 +319,9 @@ func TestCreateOrUpdateLokiStack_SetsOwnerRefOnAllObjects(t *testing.T) {
 		return nil
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 	require.NoError(t, err)
 
 	// make sure create was called
@@ -337,6 +353,7 @@ func TestCreateOrUpdateLokiStack_WhenSetControllerRefInvalid_ContinueWithOtherOb
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -354,13 +371,14 @@ func TestCreateOrUpdateLokiStack_WhenSetControllerRefInvalid_ContinueWithOtherOb
 		return nil
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.Error(t, err)
 }
 
 func TestCreateOrUpdateLokiStack_WhenGetReturnsNoError_UpdateObjects(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
 	k := &k8sfakes.FakeClient{}
 	r := ctrl.Request{
 		NamespacedName: types.NamespacedName{
@@ -382,6 +400,7 @@ func TestCreateOrUpdateLokiStack_WhenGetReturnsNoError_UpdateObjects(t *testing.
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -446,7 +465,9 @@ func TestCreateOrUpdateLokiStack_WhenGetReturnsNoError_UpdateObjects(t *testing.
 		return nil
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 	require.NoError(t, err)
 
 	// make sure create not called
@@ -478,6 +499,7 @@ func TestCreateOrUpdateLokiStack_WhenCreateReturnsError_ContinueWithOtherObjects
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -503,12 +525,81 @@ func TestCreateOrUpdateLokiStack_WhenCreateReturnsError_ContinueWithOtherObjects
 		return apierrors.NewTooManyRequestsError("too many create requests")
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.Error(t, err)
 }
 
+func TestCreateOrUpdateLokiStack_RecordsEventPerObject(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	// Fail to create the distributor only
+	k.CreateStub = func(_ context.Context, o client.Object, _ ...client.CreateOption) error {
+		if _, ok := o.(*appsv1.Deployment); ok && o.GetName() == manifests.DistributorName(stack.Name) {
+			return apierrors.NewTooManyRequestsError("too many create requests")
+		}
+		return nil
+	}
+
+	// Obsolete objects are gone already
+	k.DeleteStub = func(_ context.Context, _ client.Object, _ ...client.DeleteOption) error {
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	rec := record.NewFakeRecorder(100)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, rec, scheme, &objectstoragefakes.FakeProber{}, flags)
+	require.Error(t, err)
+
+	close(rec.Events)
+	var events []string
+	for e := range rec.Events {
+		events = append(events, e)
+	}
+
+	require.Len(t, events, k.CreateCallCount())
+	require.Contains(t, events, "Normal CreatedComponents Created ConfigMap loki-config-my-stack")
+	require.Contains(t, events, "Warning FailedComponents Failed to configure Deployment loki-distributor-my-stack: Too many requests: too many create requests")
+}
+
 func TestCreateOrUpdateLokiStack_WhenUpdateReturnsError_ContinueWithOtherObjects(t *testing.T) {
 	k := &k8sfakes.FakeClient{}
 	r := ctrl.Request{
@@ -531,6 +622,7 @@ func TestCreateOrUpdateLokiStack_WhenUpdateReturnsError_ContinueWithOtherObjects
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -601,7 +693,7 @@ func TestCreateOrUpdateLokiStack_WhenUpdateReturnsError_ContinueWithOtherObjects
 		return apierrors.NewTooManyRequestsError("too many create requests")
 	}
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.Error(t, err)
@@ -630,6 +722,7 @@ func TestCreateOrUpdateLokiStack_WhenMissingSecret_SetDegraded(t *testing.T) {
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -648,7 +741,7 @@ func TestCreateOrUpdateLokiStack_WhenMissingSecret_SetDegraded(t *testing.T) {
 
 	k.StatusStub = func() client.StatusWriter { return sw }
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.NoError(t, err)
@@ -681,6 +774,7 @@ func TestCreateOrUpdateLokiStack_WhenInvalidSecret_SetDegraded(t *testing.T) {
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: invalidSecret.Name,
 				},
 			},
@@ -703,7 +797,208 @@ func TestCreateOrUpdateLokiStack_WhenInvalidSecret_SetDegraded(t *testing.T) {
 
 	k.StatusStub = func() client.StatusWriter { return sw }
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, flags)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenMissingCAConfigMap_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+				TLS: &lokiv1beta1.ObjectStorageTLSSpec{
+					CA: "storage-ca",
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonMissingObjectStorageCAConfigMap), cond.Reason)
+}
+
+func TestCreateOrUpdateLokiStack_WhenInvalidSchemas_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+				Schemas: []lokiv1beta1.ObjectStorageSchema{
+					{
+						Version:       lokiv1beta1.ObjectStorageSchemaV12,
+						EffectiveDate: "2020-10-01",
+					},
+				},
+			},
+		},
+		Status: lokiv1beta1.LokiStackStatus{
+			Storage: lokiv1beta1.LokiStackStorageStatus{
+				Schemas: []lokiv1beta1.ObjectStorageSchema{
+					{
+						Version:       lokiv1beta1.ObjectStorageSchemaV11,
+						EffectiveDate: "2020-10-01",
+						IndexStore:    lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper,
+						ObjectStore:   lokiv1beta1.ObjectStorageSecretS3,
+					},
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonInvalidObjectStorageSchema), cond.Reason)
+}
+
+func TestCreateOrUpdateLokiStack_WhenInvalidEncryption_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+				SSE: &lokiv1beta1.ObjectStorageSSESpec{
+					Type:     lokiv1beta1.ObjectStorageSSES3,
+					KMSKeyID: "my-key",
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.NoError(t, err)
@@ -711,6 +1006,768 @@ func TestCreateOrUpdateLokiStack_WhenInvalidSecret_SetDegraded(t *testing.T) {
 	// make sure status and status-update calls
 	require.NotZero(t, k.StatusCallCount())
 	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonInvalidObjectStorageEncryption), cond.Reason)
+}
+
+func TestCreateOrUpdateLokiStack_WhenInvalidRules_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Rules: &lokiv1beta1.RulesSpec{
+				Enabled: true,
+			},
+		},
+	}
+
+	alertingRules := &lokiv1beta1.AlertingRuleList{
+		Items: []lokiv1beta1.AlertingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "alerts",
+					Namespace: "some-ns",
+				},
+				Spec: lokiv1beta1.AlertingRuleSpec{
+					TenantID: "application",
+					Groups: []*lokiv1beta1.AlertingRuleGroup{
+						{
+							Name: "group",
+							Rules: []*lokiv1beta1.AlertingRuleGroupSpec{
+								{
+									Alert: "HighErrorRate",
+									Expr:  `sum(rate({app="foo"}[5m])`,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		if _, ok := list.(*lokiv1beta1.AlertingRuleList); ok {
+			k.SetClientObjectList(list, alertingRules)
+		}
+		return nil
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonInvalidRulesConfiguration), cond.Reason)
+	require.Equal(t, "Invalid rules configuration: AlertingRule alerts: invalid LogQL expression: unclosed brackets", cond.Message)
+
+	// make sure no objects are created for an invalid rules configuration
+	require.Zero(t, k.CreateCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenInvalidCaches_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Caches: &lokiv1beta1.CachesSpec{
+				Results: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheRedis,
+					Managed: true,
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonInvalidCacheConfiguration), cond.Reason)
+	require.Equal(t, "Invalid cache configuration: results cache: managed caches must be of type memcached", cond.Message)
+
+	// make sure no objects are created for an invalid cache configuration
+	require.Zero(t, k.CreateCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenPVCShrinks_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	pvcSize := resource.MustParse("500Mi")
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					PVCSize: &pvcSize,
+				},
+			},
+		},
+	}
+
+	ingester := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      manifests.IngesterName(stack.Name),
+			Namespace: "some-ns",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "storage",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("1Gi"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		if ingester.Name == name.Name {
+			if _, ok := object.(*appsv1.StatefulSet); ok {
+				k.SetClientObject(object, ingester)
+				return nil
+			}
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonPVCShrinkNotSupported), cond.Reason)
+	require.Equal(t, "Invalid PVC size: loki-ingester-my-stack: volume claim storage cannot shrink from 1Gi to 500Mi", cond.Message)
+
+	// make sure no objects are created or updated
+	require.Zero(t, k.CreateCallCount())
+	require.Zero(t, k.UpdateCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenPVCGrowsWithoutVolumeExpansion_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	pvcSize := resource.MustParse("20Gi")
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size:             lokiv1beta1.SizeOneXExtraSmall,
+			StorageClassName: "standard",
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					PVCSize: &pvcSize,
+				},
+			},
+		},
+	}
+
+	ingester := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      manifests.IngesterName(stack.Name),
+			Namespace: "some-ns",
+		},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "storage",
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("10Gi"),
+							},
+						},
+						StorageClassName: pointer.StringPtr("standard"),
+					},
+				},
+			},
+		},
+	}
+
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "standard",
+		},
+		AllowVolumeExpansion: pointer.BoolPtr(false),
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		switch object.(type) {
+		case *appsv1.StatefulSet:
+			if ingester.Name == name.Name {
+				k.SetClientObject(object, ingester)
+				return nil
+			}
+		case *storagev1.StorageClass:
+			if storageClass.Name == name.Name {
+				k.SetClientObject(object, storageClass)
+				return nil
+			}
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonVolumeExpansionNotSupported), cond.Reason)
+	require.Equal(t, "Invalid PVC size: loki-ingester-my-stack: volume claim storage cannot be expanded, storage class standard does not allow volume expansion", cond.Message)
+
+	// make sure no claims are patched and no objects are created, updated or deleted
+	require.Zero(t, k.PatchCallCount())
+	require.Zero(t, k.CreateCallCount())
+	require.Zero(t, k.UpdateCallCount())
+	require.Zero(t, k.DeleteCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenAutoscalingDisabled_DeleteHorizontalPodAutoscaler(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Querier: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{
+						MinReplicas: 1,
+						MaxReplicas: 3,
+					},
+				},
+			},
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	// Only the distributor is still autoscaled from before
+	k.DeleteStub = func(_ context.Context, o client.Object, _ ...client.DeleteOption) error {
+		if o.GetName() == manifests.DistributorName(stack.Name) {
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	rec := record.NewFakeRecorder(100)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, rec, scheme, &objectstoragefakes.FakeProber{}, flags)
+	require.NoError(t, err)
+
+	var deleted []string
+	for i := 0; i < k.DeleteCallCount(); i++ {
+		_, obj, _ := k.DeleteArgsForCall(i)
+		if _, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler); ok {
+			require.Equal(t, "some-ns", obj.GetNamespace())
+			deleted = append(deleted, obj.GetName())
+		}
+	}
+
+	// make sure the autoscaler of the querier is kept
+	require.NotContains(t, deleted, manifests.QuerierName(stack.Name))
+	require.Contains(t, deleted, manifests.DistributorName(stack.Name))
+
+	close(rec.Events)
+	var events []string
+	for e := range rec.Events {
+		events = append(events, e)
+	}
+	require.Contains(t, events, "Normal DeletedComponents Deleted HorizontalPodAutoscaler loki-distributor-my-stack")
+}
+
+func TestCreateOrUpdateLokiStack_WhenIndexGatewayNotDefined_DeleteIndexGateway(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	rec := record.NewFakeRecorder(100)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, rec, scheme, &objectstoragefakes.FakeProber{}, flags)
+	require.NoError(t, err)
+
+	var deleted []string
+	for i := 0; i < k.DeleteCallCount(); i++ {
+		_, obj, _ := k.DeleteArgsForCall(i)
+		require.Equal(t, "some-ns", obj.GetNamespace())
+		deleted = append(deleted, fmt.Sprintf("%T/%s", obj, obj.GetName()))
+	}
+
+	require.Contains(t, deleted, "*v1.StatefulSet/loki-index-gateway-my-stack")
+	require.Contains(t, deleted, "*v1.Service/loki-index-gateway-grpc-my-stack")
+	require.Contains(t, deleted, "*v1.Service/loki-index-gateway-http-my-stack")
+	require.Contains(t, deleted, "*v1.PodDisruptionBudget/loki-index-gateway-my-stack")
+
+	close(rec.Events)
+	var events []string
+	for e := range rec.Events {
+		events = append(events, e)
+	}
+	require.Contains(t, events, "Normal DeletedComponents Deleted StatefulSet loki-index-gateway-my-stack")
+}
+
+func TestCreateOrUpdateLokiStack_WhenCacheNotManaged_DeleteMemcached(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+			Caches: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{"redis.cache.svc:6379"},
+				},
+				Results: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+			},
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	rec := record.NewFakeRecorder(100)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, rec, scheme, &objectstoragefakes.FakeProber{}, flags)
+	require.NoError(t, err)
+
+	var deleted []string
+	for i := 0; i < k.DeleteCallCount(); i++ {
+		_, obj, _ := k.DeleteArgsForCall(i)
+		require.Equal(t, "some-ns", obj.GetNamespace())
+		deleted = append(deleted, fmt.Sprintf("%T/%s", obj, obj.GetName()))
+	}
+
+	// the chunks cache moved to redis
+	require.Contains(t, deleted, "*v1.StatefulSet/loki-memcached-chunks-my-stack")
+	require.Contains(t, deleted, "*v1.Service/loki-memcached-chunks-my-stack")
+	require.Contains(t, deleted, "*v1.PodDisruptionBudget/loki-memcached-chunks-my-stack")
+
+	// make sure the managed results cache is kept
+	require.NotContains(t, deleted, "*v1.StatefulSet/loki-memcached-results-my-stack")
+	require.NotContains(t, deleted, "*v1.Service/loki-memcached-results-my-stack")
+	require.NotContains(t, deleted, "*v1.PodDisruptionBudget/loki-memcached-results-my-stack")
+}
+
+func TestCreateOrUpdateLokiStack_WhenObjectStorageUnreachable_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	p := &objectstoragefakes.FakeProber{}
+	p.ProbeReturns(&objectstorage.BucketError{
+		Bucket: "loki",
+		Err:    errors.New("connection refused"),
+	})
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, p, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.Error(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonObjectStorageUnreachable), cond.Reason)
+	require.Equal(t, "Object storage unreachable: bucket loki: connection refused", cond.Message)
+
+	// make sure no objects are applied
+	require.Zero(t, k.CreateCallCount())
+	require.Zero(t, k.UpdateCallCount())
+}
+
+func TestCreateOrUpdateLokiStack_WhenInvalidCAConfigMap_SetDegraded(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	stack := &lokiv1beta1.LokiStack{
+		TypeMeta: metav1.TypeMeta{
+			Kind: "LokiStack",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+			UID:       "b23f9a38-9672-499f-8c29-15ede74d3ece",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Storage: lokiv1beta1.ObjectStorageSpec{
+				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
+					Name: defaultSecret.Name,
+				},
+				TLS: &lokiv1beta1.ObjectStorageTLSSpec{
+					CA: "storage-ca",
+				},
+			},
+		},
+	}
+
+	// GetStub looks up the CR first, so we need to return our fake stack
+	// return NotFound for everything else to trigger create.
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, stack)
+			return nil
+		}
+		if defaultSecret.Name == name.Name {
+			k.SetClientObject(object, &defaultSecret)
+			return nil
+		}
+		if name.Name == "storage-ca" {
+			k.SetClientObject(object, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "storage-ca",
+					Namespace: "some-ns",
+				},
+				Data: map[string]string{
+					"ca.crt": "test",
+				},
+			})
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something is not found")
+	}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, flags)
+
+	// make sure error is returned to re-trigger reconciliation
+	require.NoError(t, err)
+
+	// make sure status and status-update calls
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	cond := obj.(*lokiv1beta1.LokiStack).Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonInvalidObjectStorageCAConfigMap), cond.Reason)
 }
 
 func TestCreateOrUpdateLokiStack_WhenInvalidTenantsConfiguration_SetDegraded(t *testing.T) {
@@ -740,6 +1797,7 @@ func TestCreateOrUpdateLokiStack_WhenInvalidTenantsConfiguration_SetDegraded(t *
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -777,7 +1835,7 @@ func TestCreateOrUpdateLokiStack_WhenInvalidTenantsConfiguration_SetDegraded(t *
 
 	k.StatusStub = func() client.StatusWriter { return sw }
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, ff)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, ff)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.NoError(t, err)
@@ -814,6 +1872,7 @@ func TestCreateOrUpdateLokiStack_WhenMissingGatewaySecret_SetDegraded(t *testing
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -856,7 +1915,7 @@ func TestCreateOrUpdateLokiStack_WhenMissingGatewaySecret_SetDegraded(t *testing
 
 	k.StatusStub = func() client.StatusWriter { return sw }
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, ff)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, ff)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.Error(t, err)
@@ -893,6 +1952,7 @@ func TestCreateOrUpdateLokiStack_WhenInvalidGatewaySecret_SetDegraded(t *testing
 			Size: lokiv1beta1.SizeOneXExtraSmall,
 			Storage: lokiv1beta1.ObjectStorageSpec{
 				Secret: lokiv1beta1.ObjectStorageSecretSpec{
+					Type: lokiv1beta1.ObjectStorageSecretS3,
 					Name: defaultSecret.Name,
 				},
 			},
@@ -939,7 +1999,7 @@ func TestCreateOrUpdateLokiStack_WhenInvalidGatewaySecret_SetDegraded(t *testing
 
 	k.StatusStub = func() client.StatusWriter { return sw }
 
-	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, scheme, ff)
+	err := handlers.CreateOrUpdateLokiStack(context.TODO(), r, k, &record.FakeRecorder{}, scheme, &objectstoragefakes.FakeProber{}, ff)
 
 	// make sure error is returned to re-trigger reconciliation
 	require.Error(t, err)
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotatePodWithAvailabilityZone annotates a scheduled ingester pod with the availability
+// zone of its node. The zone consists of the node's values for the topology keys of the
+// LokiStack. The ingester reads the annotation via the downward API. If the node lacks a
+// topology key, it sets the Degraded condition on the LokiStack and returns an error.
+func AnnotatePodWithAvailabilityZone(ctx context.Context, req ctrl.Request, k k8s.Client, rec record.EventRecorder) error {
+	ll := log.WithValues("pod", req.NamespacedName, "event", "annotateAvailabilityZone")
+
+	var pod corev1.Pod
+	if err := k.Get(ctx, req.NamespacedName, &pod); err != nil {
+		if apierrors.IsNotFound(err) {
+			// The pod was deleted before it got annotated, nothing to do.
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup pod", "name", req.NamespacedName)
+	}
+
+	if pod.Spec.NodeName == "" {
+		return nil
+	}
+
+	if _, ok := pod.Annotations[manifests.AnnotationAvailabilityZone]; ok {
+		return nil
+	}
+
+	var stack lokiv1beta1.LokiStack
+	key := client.ObjectKey{Name: pod.Labels[manifests.LabelStackName], Namespace: pod.Namespace}
+	if err := k.Get(ctx, key, &stack); err != nil {
+		if apierrors.IsNotFound(err) {
+			ll.Info("skipping pod of unknown lokistack", "lokistack", key)
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup lokistack", "name", key)
+	}
+
+	if stack.Spec.ZoneAwareness == nil {
+		return nil
+	}
+
+	var node corev1.Node
+	if err := k.Get(ctx, client.ObjectKey{Name: pod.Spec.NodeName}, &node); err != nil {
+		return kverrors.Wrap(err, "failed to lookup node", "name", pod.Spec.NodeName)
+	}
+
+	var values []string
+	for _, topologyKey := range stack.Spec.ZoneAwareness.TopologyKeys {
+		value, ok := node.Labels[topologyKey]
+		if !ok {
+			statusErr := status.SetDegradedCondition(ctx, k, rec, ctrl.Request{NamespacedName: key},
+				fmt.Sprintf("Missing topology key %s on node %s of ingester pod %s", topologyKey, node.Name, pod.Name),
+				lokiv1beta1.ReasonMissingNodeTopologyKey,
+			)
+			if statusErr != nil {
+				return statusErr
+			}
+
+			return kverrors.New("missing topology key on node",
+				"node", node.Name,
+				"key", topologyKey,
+			)
+		}
+		values = append(values, value)
+	}
+
+	patch := client.MergeFrom(pod.DeepCopy())
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[manifests.AnnotationAvailabilityZone] = strings.Join(values, "_")
+
+	if err := k.Patch(ctx, &pod, patch); err != nil {
+		return kverrors.Wrap(err, "failed to annotate pod with availability zone", "name", req.NamespacedName)
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package handlers_test
+
+import (
+	"context"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	zoneAwarePodRequest = ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "loki-ingester-my-stack-0",
+			Namespace: "some-ns",
+		},
+	}
+
+	zoneAwareStack = lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+		Spec: lokiv1beta1.LokiStackSpec{
+			ZoneAwareness: &lokiv1beta1.ZoneAwarenessSpec{
+				TopologyKeys: []string{
+					"topology.kubernetes.io/region",
+					"topology.kubernetes.io/zone",
+				},
+			},
+		},
+	}
+)
+
+func newZoneAwarePodClient(pod *corev1.Pod, stack *lokiv1beta1.LokiStack, node *corev1.Node) *k8sfakes.FakeClient {
+	k := &k8sfakes.FakeClient{}
+	k.GetStub = func(_ context.Context, name types.NamespacedName, out client.Object) error {
+		switch {
+		case pod != nil && name.Name == pod.Name:
+			k.SetClientObject(out, pod)
+			return nil
+		case stack != nil && name.Name == stack.Name:
+			k.SetClientObject(out, stack)
+			return nil
+		case node != nil && name.Name == node.Name:
+			k.SetClientObject(out, node)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+	return k
+}
+
+func newIngesterPod(nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      zoneAwarePodRequest.Name,
+			Namespace: zoneAwarePodRequest.Namespace,
+			Labels:    manifests.ComponentLabels(manifests.LabelIngesterComponent, zoneAwareStack.Name),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+		},
+	}
+}
+
+func TestAnnotatePodWithAvailabilityZone_AnnotatesNodeZone(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-a",
+			Labels: map[string]string{
+				"topology.kubernetes.io/region": "eu-central-1",
+				"topology.kubernetes.io/zone":   "eu-central-1a",
+			},
+		},
+	}
+	k := newZoneAwarePodClient(newIngesterPod(node.Name), &zoneAwareStack, node)
+
+	err := handlers.AnnotatePodWithAvailabilityZone(context.TODO(), zoneAwarePodRequest, k, &record.FakeRecorder{})
+	require.NoError(t, err)
+
+	require.Equal(t, 1, k.PatchCallCount())
+	_, obj, _, _ := k.PatchArgsForCall(0)
+	require.Equal(t, "eu-central-1_eu-central-1a", obj.GetAnnotations()[manifests.AnnotationAvailabilityZone])
+}
+
+func TestAnnotatePodWithAvailabilityZone_WhenMissingTopologyKey_SetDegraded(t *testing.T) {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-a",
+			Labels: map[string]string{
+				"topology.kubernetes.io/zone": "eu-central-1a",
+			},
+		},
+	}
+	k := newZoneAwarePodClient(newIngesterPod(node.Name), &zoneAwareStack, node)
+	sw := &k8sfakes.FakeStatusWriter{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	err := handlers.AnnotatePodWithAvailabilityZone(context.TODO(), zoneAwarePodRequest, k, &record.FakeRecorder{})
+
+	// make sure error is returned to re-trigger reconciliation
+	require.Error(t, err)
+	require.Zero(t, k.PatchCallCount())
+
+	require.Equal(t, 1, sw.UpdateCallCount())
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	stack := obj.(*lokiv1beta1.LokiStack)
+	require.Equal(t, zoneAwareStack.Name, stack.Name)
+
+	cond := stack.Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ReasonMissingNodeTopologyKey), cond.Reason)
+	require.Equal(t, "Missing topology key topology.kubernetes.io/region on node node-a of ingester pod loki-ingester-my-stack-0", cond.Message)
+}
+
+func TestAnnotatePodWithAvailabilityZone_SkipsPods(t *testing.T) {
+	annotated := newIngesterPod("node-a")
+	annotated.Annotations = map[string]string{
+		manifests.AnnotationAvailabilityZone: "eu-central-1a",
+	}
+
+	notZoneAware := zoneAwareStack.DeepCopy()
+	notZoneAware.Spec.ZoneAwareness = nil
+
+	table := []struct {
+		desc  string
+		pod   *corev1.Pod
+		stack *lokiv1beta1.LokiStack
+	}{
+		{
+			desc:  "pod not found",
+			stack: &zoneAwareStack,
+		},
+		{
+			desc:  "pod not scheduled",
+			pod:   newIngesterPod(""),
+			stack: &zoneAwareStack,
+		},
+		{
+			desc:  "pod already annotated",
+			pod:   annotated,
+			stack: &zoneAwareStack,
+		},
+		{
+			desc: "lokistack not found",
+			pod:  newIngesterPod("node-a"),
+		},
+		{
+			desc:  "lokistack not zone-aware",
+			pod:   newIngesterPod("node-a"),
+			stack: notZoneAware,
+		},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.desc, func(t *testing.T) {
+			t.Parallel()
+
+			k := newZoneAwarePodClient(tst.pod, tst.stack, nil)
+
+			err := handlers.AnnotatePodWithAvailabilityZone(context.TODO(), zoneAwarePodRequest, k, &record.FakeRecorder{})
+			require.NoError(t, err)
+			require.Zero(t, k.PatchCallCount())
+		})
+	}
+}
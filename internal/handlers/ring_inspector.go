@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ViaQ/logerr/kverrors"
+	"github.com/ViaQ/logerr/log"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// InspectRing publishes the state of the ingester ring, as seen by the distributors,
+// into the lokistack status. It sets the Degraded condition and returns true while
+// ring members are pending or unhealthy, or the ring cannot be read. It runs before
+// the status refresh, which keeps the Degraded condition while the ring is unhealthy.
+func InspectRing(ctx context.Context, req ctrl.Request, k k8s.Client, rec record.EventRecorder, c ingester.Client, flags manifests.FeatureFlags) (bool, error) {
+	ll := log.WithValues("lokistack", req.NamespacedName, "event", "inspectRing")
+
+	var stack lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &stack); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	// The ring is served by the distributors, skip until one is running.
+	if len(stack.Status.Components.Distributor[corev1.PodRunning]) == 0 {
+		return false, nil
+	}
+
+	members, err := c.Ring(ctx, distributorEndpoint(&stack, flags))
+	if err != nil {
+		// Keep reconciling, the ring is read again on the next inspection.
+		ll.Error(err, "failed to lookup ingester ring")
+		return true, status.SetDegradedCondition(ctx, k, rec, req,
+			fmt.Sprintf("Failed to read the ingester ring: %s", err),
+			lokiv1beta1.ReasonRingUnreachable,
+		)
+	}
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID < members[j].ID
+	})
+
+	ring := &lokiv1beta1.RingStatus{}
+	var unhealthy []string
+	for _, m := range members {
+		ring.Ingesters = append(ring.Ingesters, lokiv1beta1.RingMemberStatus{
+			Name:           m.ID,
+			State:          string(m.State),
+			Zone:           m.Zone,
+			Tokens:         int32(m.Tokens),
+			TokenOwnership: fmt.Sprintf("%.2f%%", m.Ownership*100),
+		})
+
+		switch m.State {
+		case ingester.RingStatePending, ingester.RingStateUnhealthy:
+			unhealthy = append(unhealthy, fmt.Sprintf("%s (%s)", m.ID, m.State))
+		}
+	}
+
+	if err := status.SetRingStatus(ctx, k, req, ring); err != nil {
+		return false, err
+	}
+
+	if len(unhealthy) == 0 {
+		return false, nil
+	}
+
+	ll.Info("unhealthy ingester ring members", "members", unhealthy)
+
+	return true, status.SetDegradedCondition(ctx, k, rec, req,
+		fmt.Sprintf("Unhealthy ingester ring members: %s", strings.Join(unhealthy, ", ")),
+		lokiv1beta1.ReasonUnhealthyRingMembers,
+	)
+}
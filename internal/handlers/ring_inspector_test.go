@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/ViaQ/loki-operator/internal/external/ingester/ingesterfakes"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/handlers"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func setupRingInspection(distributors []string) (*k8sfakes.FakeClient, *k8sfakes.FakeStatusWriter) {
+	stack := &lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+		Status: lokiv1beta1.LokiStackStatus{
+			Components: lokiv1beta1.LokiStackComponentStatus{
+				Distributor: lokiv1beta1.PodStatusMap{
+					corev1.PodRunning: distributors,
+				},
+			},
+		},
+	}
+
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+	k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+		k.SetClientObject(object, stack)
+		return nil
+	}
+
+	return k, sw
+}
+
+func TestInspectRing_WhenNoDistributorRunning_DoNothing(t *testing.T) {
+	k, sw := setupRingInspection(nil)
+	c := &ingesterfakes.FakeClient{}
+
+	unhealthy, err := handlers.InspectRing(context.TODO(), rolloutRequest, k, &record.FakeRecorder{}, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.False(t, unhealthy)
+	require.Zero(t, c.RingCallCount())
+	require.Zero(t, sw.UpdateCallCount())
+}
+
+func TestInspectRing_WhenAllMembersActive_SetRingStatus(t *testing.T) {
+	k, sw := setupRingInspection([]string{"loki-distributor-my-stack-abc"})
+	c := &ingesterfakes.FakeClient{}
+	c.RingReturns([]ingester.RingMember{
+		{ID: "loki-ingester-my-stack-1", State: ingester.RingStateActive, Tokens: 512, Ownership: 0.4875},
+		{ID: "loki-ingester-my-stack-0", State: ingester.RingStateLeaving, Zone: "eu-west-1a", Tokens: 512, Ownership: 0.5125},
+	}, nil)
+
+	unhealthy, err := handlers.InspectRing(context.TODO(), rolloutRequest, k, &record.FakeRecorder{}, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.False(t, unhealthy)
+
+	_, ep := c.RingArgsForCall(0)
+	require.Equal(t, "http://loki-distributor-http-my-stack.some-ns.svc:3100", ep.URL)
+
+	require.Equal(t, 1, sw.UpdateCallCount())
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	stack := obj.(*lokiv1beta1.LokiStack)
+
+	want := &lokiv1beta1.RingStatus{
+		Ingesters: []lokiv1beta1.RingMemberStatus{
+			{Name: "loki-ingester-my-stack-0", State: "LEAVING", Zone: "eu-west-1a", Tokens: 512, TokenOwnership: "51.25%"},
+			{Name: "loki-ingester-my-stack-1", State: "ACTIVE", Tokens: 512, TokenOwnership: "48.75%"},
+		},
+	}
+	require.Equal(t, want, stack.Status.Ring)
+	require.Empty(t, stack.Status.Conditions)
+}
+
+func TestInspectRing_WhenMembersUnhealthy_SetDegraded(t *testing.T) {
+	k, sw := setupRingInspection([]string{"loki-distributor-my-stack-abc"})
+	c := &ingesterfakes.FakeClient{}
+	c.RingReturns([]ingester.RingMember{
+		{ID: "loki-ingester-my-stack-0", State: ingester.RingStateActive, Tokens: 512, Ownership: 1},
+		{ID: "loki-ingester-my-stack-1", State: ingester.RingStatePending},
+		{ID: "loki-ingester-my-stack-2", State: ingester.RingStateUnhealthy},
+	}, nil)
+
+	unhealthy, err := handlers.InspectRing(context.TODO(), rolloutRequest, k, &record.FakeRecorder{}, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.True(t, unhealthy)
+
+	// Ring status first, then the degraded condition
+	require.Equal(t, 2, sw.UpdateCallCount())
+	_, obj, _ := sw.UpdateArgsForCall(1)
+	stack := obj.(*lokiv1beta1.LokiStack)
+
+	require.Len(t, stack.Status.Conditions, 1)
+	cond := stack.Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ConditionDegraded), cond.Type)
+	require.Equal(t, metav1.ConditionTrue, cond.Status)
+	require.Equal(t, string(lokiv1beta1.ReasonUnhealthyRingMembers), cond.Reason)
+	require.Equal(t, "Unhealthy ingester ring members: loki-ingester-my-stack-1 (PENDING), loki-ingester-my-stack-2 (UNHEALTHY)", cond.Message)
+}
+
+func TestInspectRing_WhenRingLookupFails_SetDegraded(t *testing.T) {
+	k, sw := setupRingInspection([]string{"loki-distributor-my-stack-abc"})
+	c := &ingesterfakes.FakeClient{}
+	c.RingReturns(nil, errors.New("connection refused"))
+
+	unhealthy, err := handlers.InspectRing(context.TODO(), rolloutRequest, k, &record.FakeRecorder{}, c, manifests.FeatureFlags{})
+	require.NoError(t, err)
+	require.True(t, unhealthy)
+
+	require.Equal(t, 1, sw.UpdateCallCount())
+	_, obj, _ := sw.UpdateArgsForCall(0)
+	stack := obj.(*lokiv1beta1.LokiStack)
+
+	require.Len(t, stack.Status.Conditions, 1)
+	cond := stack.Status.Conditions[0]
+	require.Equal(t, string(lokiv1beta1.ConditionDegraded), cond.Type)
+	require.Equal(t, string(lokiv1beta1.ReasonRingUnreachable), cond.Reason)
+	require.Equal(t, "Failed to read the ingester ring: connection refused", cond.Message)
+}
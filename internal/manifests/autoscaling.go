@@ -0,0 +1,127 @@
+package manifests
+
+import (
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultTargetCPUUtilization is used if neither a CPU target nor a custom metric is defined.
+const defaultTargetCPUUtilization = 80
+
+// newHorizontalPodAutoscaler creates a horizontal pod autoscaler scaling the
+// given target object by CPU utilization and/or a custom per pod metric.
+func newHorizontalPodAutoscaler(name, kind string, l labels.Set, spec *lokiv1beta1.AutoscalingSpec) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	var metrics []autoscalingv2beta2.MetricSpec
+
+	cpu := spec.TargetCPUUtilization
+	if cpu == nil && spec.CustomMetric == nil {
+		cpu = pointer.Int32Ptr(defaultTargetCPUUtilization)
+	}
+
+	if cpu != nil {
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: pointer.Int32Ptr(*cpu),
+				},
+			},
+		})
+	}
+
+	if m := spec.CustomMetric; m != nil {
+		value := m.TargetAverageValue.DeepCopy()
+		metrics = append(metrics, autoscalingv2beta2.MetricSpec{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{
+					Name: m.Name,
+				},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		})
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "HorizontalPodAutoscaler",
+			APIVersion: autoscalingv2beta2.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: l,
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       kind,
+				Name:       name,
+			},
+			MinReplicas: pointer.Int32Ptr(spec.MinReplicas),
+			MaxReplicas: spec.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+// BuildObsoleteHorizontalPodAutoscalers returns the horizontal pod autoscalers of all
+// components not autoscaled. These must be deleted, otherwise they keep scaling the
+// component while the operator sets its replicas.
+func BuildObsoleteHorizontalPodAutoscalers(opts Options) []client.Object {
+	tpl := opts.Stack.Template
+
+	var names []string
+	if !autoscalingEnabled(tpl.Distributor) {
+		names = append(names, DistributorName(opts.Name))
+	}
+	if !autoscalingEnabled(tpl.Querier) {
+		names = append(names, QuerierName(opts.Name))
+	}
+	if !autoscalingEnabled(tpl.QueryFrontend) {
+		names = append(names, QueryFrontendName(opts.Name))
+	}
+	if !opts.Flags.EnableGateway || !autoscalingEnabled(tpl.Gateway) {
+		names = append(names, GatewayName(opts.Name))
+	}
+
+	objs := make([]client.Object, 0, len(names))
+	for _, name := range names {
+		objs = append(objs, &autoscalingv2beta2.HorizontalPodAutoscaler{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "HorizontalPodAutoscaler",
+				APIVersion: autoscalingv2beta2.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		})
+	}
+
+	return objs
+}
+
+// autoscalingEnabled returns true if the replicas of the component are
+// managed by a horizontal pod autoscaler.
+func autoscalingEnabled(spec *lokiv1beta1.LokiComponentSpec) bool {
+	return spec != nil && spec.Autoscaling != nil
+}
+
+// componentReplicas returns the replicas of the component or nil if
+// they are managed by a horizontal pod autoscaler.
+func componentReplicas(spec *lokiv1beta1.LokiComponentSpec) *int32 {
+	if autoscalingEnabled(spec) {
+		return nil
+	}
+	return pointer.Int32Ptr(spec.Replicas)
+}
@@ -0,0 +1,198 @@
+package manifests_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+func TestNewDistributorHorizontalPodAutoscaler_DefaultsToCPUUtilization(t *testing.T) {
+	opts := manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Distributor: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{
+						MinReplicas: 2,
+						MaxReplicas: 5,
+					},
+				},
+			},
+		},
+	}
+
+	hpa := manifests.NewDistributorHorizontalPodAutoscaler(opts)
+	require.Equal(t, autoscalingv2beta2.CrossVersionObjectReference{
+		APIVersion: "apps/v1",
+		Kind:       "Deployment",
+		Name:       manifests.DistributorName("abcd"),
+	}, hpa.Spec.ScaleTargetRef)
+	require.Equal(t, pointer.Int32Ptr(2), hpa.Spec.MinReplicas)
+	require.EqualValues(t, 5, hpa.Spec.MaxReplicas)
+	require.Equal(t, []autoscalingv2beta2.MetricSpec{
+		{
+			Type: autoscalingv2beta2.ResourceMetricSourceType,
+			Resource: &autoscalingv2beta2.ResourceMetricSource{
+				Name: corev1.ResourceCPU,
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:               autoscalingv2beta2.UtilizationMetricType,
+					AverageUtilization: pointer.Int32Ptr(80),
+				},
+			},
+		},
+	}, hpa.Spec.Metrics)
+
+	// Replicas are left to the autoscaler
+	require.Nil(t, manifests.NewDistributorDeployment(opts).Spec.Replicas)
+}
+
+func TestNewQuerierHorizontalPodAutoscaler_WithCustomMetric(t *testing.T) {
+	opts := manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Querier: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{
+						MinReplicas: 1,
+						MaxReplicas: 10,
+						CustomMetric: &lokiv1beta1.AutoscalingMetricSpec{
+							Name:               "cortex_query_scheduler_queue_length",
+							TargetAverageValue: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	hpa := manifests.NewQuerierHorizontalPodAutoscaler(opts)
+	require.Equal(t, "StatefulSet", hpa.Spec.ScaleTargetRef.Kind)
+
+	value := resource.MustParse("4")
+	require.Equal(t, []autoscalingv2beta2.MetricSpec{
+		{
+			Type: autoscalingv2beta2.PodsMetricSourceType,
+			Pods: &autoscalingv2beta2.PodsMetricSource{
+				Metric: autoscalingv2beta2.MetricIdentifier{
+					Name: "cortex_query_scheduler_queue_length",
+				},
+				Target: autoscalingv2beta2.MetricTarget{
+					Type:         autoscalingv2beta2.AverageValueMetricType,
+					AverageValue: &value,
+				},
+			},
+		},
+	}, hpa.Spec.Metrics)
+}
+
+func TestBuildAll_WithAutoscaling(t *testing.T) {
+	autoscaling := &lokiv1beta1.AutoscalingSpec{
+		MinReplicas: 1,
+		MaxReplicas: 3,
+	}
+	opts := manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Distributor:   &lokiv1beta1.LokiComponentSpec{Autoscaling: autoscaling},
+				Querier:       &lokiv1beta1.LokiComponentSpec{Autoscaling: autoscaling},
+				QueryFrontend: &lokiv1beta1.LokiComponentSpec{Autoscaling: autoscaling},
+				Gateway:       &lokiv1beta1.LokiComponentSpec{Autoscaling: autoscaling},
+			},
+			Tenants: &lokiv1beta1.TenantsSpec{
+				Mode: lokiv1beta1.Dynamic,
+				Authentication: []lokiv1beta1.AuthenticationSpec{
+					{
+						TenantName: "test",
+						TenantID:   "1234",
+						OIDC: &lokiv1beta1.OIDCSpec{
+							Secret: &lokiv1beta1.TenantSecretSpec{
+								Name: "test",
+							},
+							IssuerURL:     "https://127.0.0.1:5556/dex",
+							RedirectURL:   "https://localhost:8443/oidc/test/callback",
+							GroupClaim:    "test",
+							UsernameClaim: "test",
+						},
+					},
+				},
+				Authorization: &lokiv1beta1.AuthorizationSpec{
+					OPA: &lokiv1beta1.OPASpec{
+						URL: "http://127.0.0.1:8181/v1/data/observatorium/allow",
+					},
+				},
+			},
+		},
+		Flags: manifests.FeatureFlags{
+			EnableGateway: true,
+		},
+	}
+
+	err := manifests.ApplyDefaultSettings(&opts)
+	require.NoError(t, err)
+
+	objs, err := manifests.BuildAll(opts)
+	require.NoError(t, err)
+
+	var targets []string
+	for _, obj := range objs {
+		if hpa, ok := obj.(*autoscalingv2beta2.HorizontalPodAutoscaler); ok {
+			targets = append(targets, hpa.Spec.ScaleTargetRef.Name)
+		}
+	}
+
+	require.ElementsMatch(t, []string{
+		manifests.DistributorName("abcd"),
+		manifests.QuerierName("abcd"),
+		manifests.QueryFrontendName("abcd"),
+		manifests.GatewayName("abcd"),
+	}, targets)
+}
+
+func TestBuildObsoleteHorizontalPodAutoscalers(t *testing.T) {
+	opts := manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Distributor: &lokiv1beta1.LokiComponentSpec{
+					Autoscaling: &lokiv1beta1.AutoscalingSpec{
+						MinReplicas: 1,
+						MaxReplicas: 3,
+					},
+				},
+			},
+		},
+		Flags: manifests.FeatureFlags{
+			EnableGateway: true,
+		},
+	}
+
+	err := manifests.ApplyDefaultSettings(&opts)
+	require.NoError(t, err)
+
+	var names []string
+	for _, obj := range manifests.BuildObsoleteHorizontalPodAutoscalers(opts) {
+		require.IsType(t, &autoscalingv2beta2.HorizontalPodAutoscaler{}, obj)
+		names = append(names, obj.GetName())
+	}
+
+	require.ElementsMatch(t, []string{
+		manifests.QuerierName("abcd"),
+		manifests.QueryFrontendName("abcd"),
+		manifests.GatewayName("abcd"),
+	}, names)
+}
@@ -4,8 +4,11 @@ import (
 	"github.com/ViaQ/logerr/kverrors"
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests/internal"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 
 	"github.com/imdario/mergo"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -52,6 +55,30 @@ func BuildAll(opts Options) ([]client.Object, error) {
 	res = append(res, queryFrontendObjs...)
 	res = append(res, BuildLokiGossipRingService(opts.Name))
 
+	if rulerEnabled(opts) {
+		rulerObjs, err := BuildRuler(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, rulerObjs...)
+	}
+
+	if indexGatewayEnabled(opts) {
+		indexGatewayObjs, err := BuildIndexGateway(opts)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, indexGatewayObjs...)
+	}
+
+	res = append(res, BuildMemcached(opts)...)
+
+	if requiresServiceAccount(opts) {
+		res = append(res, BuildServiceAccount(opts))
+	}
+
 	if opts.Flags.EnableGateway {
 		gatewayObjects, err := BuildGateway(opts)
 		if err != nil {
@@ -61,6 +88,8 @@ func BuildAll(opts Options) ([]client.Object, error) {
 		res = append(res, gatewayObjects...)
 	}
 
+	res = append(res, BuildPodDisruptionBudgets(opts)...)
+
 	if opts.Flags.EnableServiceMonitors {
 		res = append(res, BuildServiceMonitors(opts)...)
 	}
@@ -68,6 +97,17 @@ func BuildAll(opts Options) ([]client.Object, error) {
 	return res, nil
 }
 
+// BuildObsolete builds the manifests of objects no longer required to run a Loki Stack,
+// e.g. after disabling an optional feature. They only identify the objects to delete.
+func BuildObsolete(opts Options) []client.Object {
+	res := BuildObsoleteHorizontalPodAutoscalers(opts)
+	res = append(res, BuildObsoleteRuler(opts)...)
+	res = append(res, BuildObsoleteIndexGateway(opts)...)
+	res = append(res, BuildObsoleteMemcached(opts)...)
+
+	return res
+}
+
 // DefaultLokiStackSpec returns the default configuration for a LokiStack of
 // the specified size
 func DefaultLokiStackSpec(size lokiv1beta1.LokiStackSizeType) *lokiv1beta1.LokiStackSpec {
@@ -98,8 +138,96 @@ func ApplyDefaultSettings(opts *Options) error {
 		return kverrors.Wrap(err, "failed to merge strict defaults")
 	}
 
-	opts.ResourceRequirements = internal.ResourceRequirementsTable[opts.Stack.Size]
+	// The index gateway is optional and not part of the size defaults.
+	if ig := spec.Template.IndexGateway; ig != nil && ig.Replicas == 0 {
+		spec.Template.IndexGateway = ig.DeepCopy()
+		spec.Template.IndexGateway.Replicas = 1
+	}
+
+	opts.ResourceRequirements = componentResources(opts.Stack.Size, spec, opts.DeployedIngesterPVCSize)
 	opts.Stack = *spec
 
+	if len(opts.ObjectStorage.Schemas) == 0 {
+		opts.ObjectStorage.Schemas = []lokiv1beta1.ObjectStorageSchema{
+			storage.DefaultSchema(opts.ObjectStorage.SharedStore),
+		}
+	}
+
 	return nil
 }
+
+// componentResources returns the resource requirements of the stack size with the
+// resources and PVC sizes of the component templates merged over them.
+func componentResources(size lokiv1beta1.LokiStackSizeType, spec *lokiv1beta1.LokiStackSpec, deployedIngesterPVCSize resource.Quantity) internal.ComponentResources {
+	res := internal.ResourceRequirementsTable[size]
+
+	if wal := spec.WriteAheadLog; wal != nil && wal.Enabled {
+		// Leave room for the write-ahead log on the ingester volumes.
+		res.Ingester.PVCSize = internal.WriteAheadLogPVCSizeTable[size]
+	} else if deployedIngesterPVCSize.Cmp(res.Ingester.PVCSize) > 0 {
+		// Keep the larger volumes of a formerly enabled write-ahead log, volume claims cannot shrink.
+		res.Ingester.PVCSize = deployedIngesterPVCSize.DeepCopy()
+	}
+
+	tpl := spec.Template
+	if tpl == nil {
+		return res
+	}
+
+	res.Querier = mergeResourceRequirements(res.Querier, tpl.Querier)
+	res.Ingester = mergeResourceRequirements(res.Ingester, tpl.Ingester)
+	res.Compactor = mergeResourceRequirements(res.Compactor, tpl.Compactor)
+	res.Ruler = mergeResourceRequirements(res.Ruler, tpl.Ruler)
+	res.IndexGateway = mergeResourceRequirements(res.IndexGateway, tpl.IndexGateway)
+	res.Distributor = mergeComputeResources(res.Distributor, tpl.Distributor)
+	res.QueryFrontend = mergeComputeResources(res.QueryFrontend, tpl.QueryFrontend)
+	res.Gateway = mergeComputeResources(res.Gateway, tpl.Gateway)
+
+	return res
+}
+
+func mergeResourceRequirements(defaults internal.ResourceRequirements, spec *lokiv1beta1.LokiComponentSpec) internal.ResourceRequirements {
+	if spec == nil {
+		return defaults
+	}
+
+	if spec.Resources != nil {
+		defaults.Limits = mergeResourceList(defaults.Limits, spec.Resources.Limits)
+		defaults.Requests = mergeResourceList(defaults.Requests, spec.Resources.Requests)
+	}
+
+	if spec.PVCSize != nil {
+		defaults.PVCSize = spec.PVCSize.DeepCopy()
+	}
+
+	return defaults
+}
+
+func mergeComputeResources(defaults corev1.ResourceRequirements, spec *lokiv1beta1.LokiComponentSpec) corev1.ResourceRequirements {
+	if spec == nil || spec.Resources == nil {
+		return defaults
+	}
+
+	defaults.Limits = mergeResourceList(defaults.Limits, spec.Resources.Limits)
+	defaults.Requests = mergeResourceList(defaults.Requests, spec.Resources.Requests)
+
+	return defaults
+}
+
+// mergeResourceList returns a copy of the defaults with the overrides applied.
+// The defaults are shared by all stacks of the same size and must not be modified.
+func mergeResourceList(defaults, overrides corev1.ResourceList) corev1.ResourceList {
+	if len(overrides) == 0 {
+		return defaults
+	}
+
+	merged := corev1.ResourceList{}
+	for name, q := range defaults {
+		merged[name] = q.DeepCopy()
+	}
+	for name, q := range overrides {
+		merged[name] = q.DeepCopy()
+	}
+
+	return merged
+}
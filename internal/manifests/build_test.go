@@ -3,11 +3,14 @@ package manifests
 import (
 	"testing"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests/internal"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,6 +83,175 @@ func TestApplyUserOptions_AlwaysSetCompactorReplicasToOne(t *testing.T) {
 	}
 }
 
+func TestApplyUserOptions_MergeResourceOverrides(t *testing.T) {
+	pvcSize := resource.MustParse("50Gi")
+	opt := Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("12Gi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("16Gi"),
+						},
+					},
+					PVCSize: &pvcSize,
+				},
+				Distributor: &lokiv1beta1.LokiComponentSpec{
+					Resources: &corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("4"),
+						},
+					},
+				},
+			},
+		},
+	}
+	err := ApplyDefaultSettings(&opt)
+	require.NoError(t, err)
+
+	defs := internal.ResourceRequirementsTable[lokiv1beta1.SizeOneXSmall]
+	res := opt.ResourceRequirements
+
+	// Require overridden resources to be merged over the size defaults
+	require.Equal(t, resource.MustParse("12Gi"), res.Ingester.Requests[corev1.ResourceMemory])
+	require.Equal(t, resource.MustParse("16Gi"), res.Ingester.Limits[corev1.ResourceMemory])
+	require.Equal(t, defs.Ingester.Requests[corev1.ResourceCPU], res.Ingester.Requests[corev1.ResourceCPU])
+	require.Equal(t, pvcSize, res.Ingester.PVCSize)
+	require.Equal(t, resource.MustParse("4"), res.Distributor.Requests[corev1.ResourceCPU])
+	require.Equal(t, defs.Distributor.Requests[corev1.ResourceMemory], res.Distributor.Requests[corev1.ResourceMemory])
+
+	// Require components without overrides to use the size defaults
+	require.Equal(t, defs.Querier, res.Querier)
+	require.Equal(t, defs.QueryFrontend, res.QueryFrontend)
+
+	// Require the size defaults to be left untouched
+	require.NotEqual(t, resource.MustParse("12Gi"), defs.Ingester.Requests[corev1.ResourceMemory])
+	require.NotEqual(t, pvcSize, defs.Ingester.PVCSize)
+
+	// Require the overrides to be applied to the component manifests
+	sts := NewIngesterStatefulSet(opt)
+	require.Equal(t, res.Ingester.Requests, sts.Spec.Template.Spec.Containers[0].Resources.Requests)
+	require.Equal(t, pvcSize, sts.Spec.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage])
+}
+
+func TestApplyUserOptions_DefaultIndexGatewayReplicasToOne(t *testing.T) {
+	opt := Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{},
+			},
+		},
+	}
+	err := ApplyDefaultSettings(&opt)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, opt.Stack.Template.IndexGateway.Replicas)
+
+	opt.Stack.Template.IndexGateway.Replicas = 3
+	err = ApplyDefaultSettings(&opt)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, opt.Stack.Template.IndexGateway.Replicas)
+}
+
+func TestApplyUserOptions_WriteAheadLogIngesterPVCSize(t *testing.T) {
+	pvcSize := resource.MustParse("20Gi")
+
+	type test struct {
+		name     string
+		wal      *lokiv1beta1.WriteAheadLogSpec
+		template *lokiv1beta1.LokiTemplateSpec
+		deployed resource.Quantity
+		want     resource.Quantity
+	}
+	table := []test{
+		{
+			name: "wal not defined",
+			want: resource.MustParse("10Gi"),
+		},
+		{
+			name: "wal disabled",
+			wal:  &lokiv1beta1.WriteAheadLogSpec{},
+			want: resource.MustParse("10Gi"),
+		},
+		{
+			name:     "wal disabled with smaller deployed volumes",
+			wal:      &lokiv1beta1.WriteAheadLogSpec{},
+			deployed: resource.MustParse("5Gi"),
+			want:     resource.MustParse("10Gi"),
+		},
+		{
+			name: "wal enabled",
+			wal:  &lokiv1beta1.WriteAheadLogSpec{Enabled: true},
+			want: resource.MustParse("50Gi"),
+		},
+		{
+			name: "wal enabled with pvc size override",
+			wal:  &lokiv1beta1.WriteAheadLogSpec{Enabled: true},
+			template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					PVCSize: &pvcSize,
+				},
+			},
+			want: pvcSize,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opt := Options{
+				Name:      "abcd",
+				Namespace: "efgh",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size:          lokiv1beta1.SizeOneXSmall,
+					WriteAheadLog: tst.wal,
+					Template:      tst.template,
+				},
+				DeployedIngesterPVCSize: tst.deployed,
+			}
+			err := ApplyDefaultSettings(&opt)
+			require.NoError(t, err)
+			require.Equal(t, tst.want, opt.ResourceRequirements.Ingester.PVCSize)
+		})
+	}
+}
+
+func TestApplyUserOptions_WriteAheadLogDisabledAgain_KeepsIngesterPVCSize(t *testing.T) {
+	enabled := Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size:          lokiv1beta1.SizeOneXSmall,
+			WriteAheadLog: &lokiv1beta1.WriteAheadLogSpec{Enabled: true},
+		},
+	}
+	err := ApplyDefaultSettings(&enabled)
+	require.NoError(t, err)
+	require.Equal(t, resource.MustParse("50Gi"), enabled.ResourceRequirements.Ingester.PVCSize)
+
+	disabled := Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size:          lokiv1beta1.SizeOneXSmall,
+			WriteAheadLog: &lokiv1beta1.WriteAheadLogSpec{},
+		},
+		DeployedIngesterPVCSize: enabled.ResourceRequirements.Ingester.PVCSize,
+	}
+	err = ApplyDefaultSettings(&disabled)
+	require.NoError(t, err)
+	require.Equal(t, resource.MustParse("50Gi"), disabled.ResourceRequirements.Ingester.PVCSize)
+}
+
 func TestBuildAll_WithFeatureFlags_EnableServiceMonitors(t *testing.T) {
 	type test struct {
 		desc         string
@@ -280,6 +452,83 @@ func TestBuildAll_WithFeatureFlags_EnableGateway(t *testing.T) {
 	}
 }
 
+func TestBuildAll_WithObjectStorageSTS(t *testing.T) {
+	type test struct {
+		desc           string
+		ServiceAccount string
+		BuildOptions   Options
+	}
+
+	table := []test{
+		{
+			desc: "static credentials use default service account",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+				},
+				ObjectStorage: storage.Options{
+					SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+					S3:          &storage.S3StorageConfig{},
+				},
+			},
+		},
+		{
+			desc:           "sts credentials use dedicated service account",
+			ServiceAccount: "loki-test",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+				},
+				ObjectStorage: storage.Options{
+					SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+					S3: &storage.S3StorageConfig{
+						STS: &storage.STSConfig{
+							RoleARN:  "arn:aws:iam::123456789012:role/loki",
+							Audience: storage.DefaultSTSAudience,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ApplyDefaultSettings(&tst.BuildOptions)
+			require.NoError(t, err)
+
+			objects, buildErr := BuildAll(tst.BuildOptions)
+			require.NoError(t, buildErr)
+
+			serviceAccounts := 0
+			for _, obj := range objects {
+				switch o := obj.(type) {
+				case *corev1.ServiceAccount:
+					require.Equal(t, tst.ServiceAccount, o.Name)
+					serviceAccounts++
+				case *appsv1.Deployment:
+					require.Equal(t, tst.ServiceAccount, o.Spec.Template.Spec.ServiceAccountName)
+				case *appsv1.StatefulSet:
+					require.Equal(t, tst.ServiceAccount, o.Spec.Template.Spec.ServiceAccountName)
+				}
+			}
+
+			if tst.ServiceAccount == "" {
+				require.Zero(t, serviceAccounts)
+			} else {
+				require.Equal(t, 1, serviceAccounts)
+			}
+		})
+	}
+}
+
 func serviceMonitorCount(objects []client.Object) int {
 	monitors := 0
 	for _, obj := range objects {
@@ -299,3 +548,136 @@ func checkGatewayDeployed(objects []client.Object, stackName string) bool {
 	}
 	return false
 }
+
+func TestBuildAll_WithRulesEnabled(t *testing.T) {
+	type test struct {
+		desc         string
+		BuildOptions Options
+		rulerObjects int
+	}
+
+	table := []test{
+		{
+			desc: "rules disabled",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+				},
+			},
+		},
+		{
+			desc: "rules enabled",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+					Rules: &lokiv1beta1.RulesSpec{
+						Enabled: true,
+					},
+				},
+				Flags: FeatureFlags{
+					EnableServiceMonitors: true,
+				},
+			},
+			// configmap, statefulset, pod disruption budget, grpc and http services, service monitor
+			rulerObjects: 6,
+		},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ApplyDefaultSettings(&tst.BuildOptions)
+			require.NoError(t, err)
+
+			objects, buildErr := BuildAll(tst.BuildOptions)
+			require.NoError(t, buildErr)
+
+			rulerObjects := 0
+			for _, obj := range objects {
+				switch obj.GetName() {
+				case RulerName("test"),
+					RulesConfigMapName("test"),
+					serviceNameRulerGRPC("test"),
+					serviceNameRulerHTTP("test"),
+					serviceMonitorName(RulerName("test")):
+					rulerObjects++
+				}
+			}
+
+			require.Equal(t, tst.rulerObjects, rulerObjects)
+		})
+	}
+}
+
+func TestBuildAll_WithIndexGatewayEnabled(t *testing.T) {
+	type test struct {
+		desc                string
+		BuildOptions        Options
+		indexGatewayObjects int
+	}
+
+	table := []test{
+		{
+			desc: "index gateway disabled",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+				},
+			},
+		},
+		{
+			desc: "index gateway enabled",
+			BuildOptions: Options{
+				Name:      "test",
+				Namespace: "test",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Size: lokiv1beta1.SizeOneXSmall,
+					Template: &lokiv1beta1.LokiTemplateSpec{
+						IndexGateway: &lokiv1beta1.LokiComponentSpec{
+							Replicas: 1,
+						},
+					},
+				},
+				Flags: FeatureFlags{
+					EnableServiceMonitors: true,
+				},
+			},
+			// statefulset, pod disruption budget, grpc and http services, service monitor
+			indexGatewayObjects: 5,
+		},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.desc, func(t *testing.T) {
+			t.Parallel()
+
+			err := ApplyDefaultSettings(&tst.BuildOptions)
+			require.NoError(t, err)
+
+			objects, buildErr := BuildAll(tst.BuildOptions)
+			require.NoError(t, buildErr)
+
+			indexGatewayObjects := 0
+			for _, obj := range objects {
+				switch obj.GetName() {
+				case IndexGatewayName("test"),
+					serviceNameIndexGatewayGRPC("test"),
+					serviceNameIndexGatewayHTTP("test"),
+					serviceMonitorName(IndexGatewayName("test")):
+					indexGatewayObjects++
+				}
+			}
+
+			require.Equal(t, tst.indexGatewayObjects, indexGatewayObjects)
+		})
+	}
+}
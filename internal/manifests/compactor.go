@@ -5,6 +5,7 @@ import (
 	"path"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -25,6 +26,10 @@ func BuildCompactor(opts Options) ([]client.Object, error) {
 		}
 	}
 
+	if err := storage.ConfigureStatefulSet(statefulSet, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
 	return []client.Object{
 		statefulSet,
 		NewCompactorGRPCService(opts),
@@ -35,6 +40,7 @@ func BuildCompactor(opts Options) ([]client.Object, error) {
 // NewCompactorStatefulSet creates a statefulset object for a compactor.
 func NewCompactorStatefulSet(opts Options) *appsv1.StatefulSet {
 	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
 		Volumes: []corev1.Volume{
 			{
 				Name: configVolumeName,
@@ -119,14 +125,9 @@ func NewCompactorStatefulSet(opts Options) *appsv1.StatefulSet {
 		},
 	}
 
-	if opts.Stack.Template != nil && opts.Stack.Template.Compactor != nil {
-		podSpec.Tolerations = opts.Stack.Template.Compactor.Tolerations
-		podSpec.NodeSelector = opts.Stack.Template.Compactor.NodeSelector
-	}
-
 	l := ComponentLabels(LabelCompactorComponent, opts.Name)
-	a := commonAnnotations(opts.ConfigSHA1)
-	return &appsv1.StatefulSet{
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
+	sts := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "StatefulSet",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -173,6 +174,13 @@ func NewCompactorStatefulSet(opts Options) *appsv1.StatefulSet {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&sts.Spec.Template, opts.Stack.Template.Compactor)
+	}
+	configureZoneAwareness(&sts.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return sts
 }
 
 // NewCompactorGRPCService creates a k8s service for the compactor GRPC endpoint
@@ -3,13 +3,20 @@ package manifests
 import (
 	"crypto/sha1"
 	"fmt"
+	"path"
 	"strings"
 
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	walDirectoryName             = "wal"
+	defaultWALCheckpointDuration = "5m"
+)
+
 // LokiConfigMap creates the single configmap containing the loki configuration for the whole cluster
 func LokiConfigMap(opt Options) (*corev1.ConfigMap, string, error) {
 	cfg := ConfigOptions(opt)
@@ -60,18 +67,140 @@ func ConfigOptions(opt Options) config.Options {
 			Port: httpPort,
 		},
 		StorageDirectory: strings.TrimRight(dataDirectory, "/"),
-		ObjectStorage: config.ObjectStorage{
-			Endpoint:        opt.ObjectStorage.Endpoint,
-			Buckets:         opt.ObjectStorage.Buckets,
-			Region:          opt.ObjectStorage.Region,
-			AccessKeyID:     opt.ObjectStorage.AccessKeyID,
-			AccessKeySecret: opt.ObjectStorage.AccessKeySecret,
-		},
+		ObjectStorage:    opt.ObjectStorage,
 		QueryParallelism: config.Parallelism{
 			QuerierCPULimits:      opt.ResourceRequirements.Querier.Requests.Cpu().Value(),
-			QueryFrontendReplicas: opt.Stack.Template.QueryFrontend.Replicas,
+			QueryFrontendReplicas: queryFrontendReplicas(opt.Stack.Template.QueryFrontend),
 		},
+		Retention: config.RetentionOptions{
+			Enabled: retentionEnabled(opt.Stack.Limits),
+		},
+		Ruler:         rulerOptions(opt),
+		IndexGateway:  indexGatewayAddress(opt),
+		Caches:        cacheOptions(opt),
+		WriteAheadLog: writeAheadLogOptions(opt),
+	}
+}
+
+// queryFrontendReplicas returns the number of query-frontends the querier workers connect to.
+// With autoscaling, it is the maximum number of replicas, so that the total parallelism of
+// each querier does not exceed its CPU when the query-frontends are scaled out.
+func queryFrontendReplicas(spec *lokiv1beta1.LokiComponentSpec) int32 {
+	if autoscalingEnabled(spec) {
+		return spec.Autoscaling.MaxReplicas
+	}
+	return spec.Replicas
+}
+
+// writeAheadLogOptions returns the write-ahead log configuration if it is enabled.
+// The log is stored on the ingester volume. The replay memory ceiling defaults to
+// 75% of the ingester memory request, as recommended by the Loki docs.
+func writeAheadLogOptions(opt Options) config.WriteAheadLog {
+	wal := opt.Stack.WriteAheadLog
+	if wal == nil || !wal.Enabled {
+		return config.WriteAheadLog{}
+	}
+
+	checkpointDuration := wal.CheckpointDuration
+	if checkpointDuration == "" {
+		checkpointDuration = defaultWALCheckpointDuration
+	}
+
+	replayMemoryCeiling := opt.ResourceRequirements.Ingester.Requests.Memory().Value() / 4 * 3
+	if wal.ReplayMemoryCeiling != nil {
+		replayMemoryCeiling = wal.ReplayMemoryCeiling.Value()
+	}
+
+	return config.WriteAheadLog{
+		Enabled:             true,
+		Directory:           path.Join(dataDirectory, walDirectoryName),
+		CheckpointDuration:  checkpointDuration,
+		ReplayMemoryCeiling: replayMemoryCeiling,
+	}
+}
+
+// cacheOptions returns the configuration of the chunks, index queries and results caches.
+// Managed caches are discovered by the SRV records of their memcached service.
+func cacheOptions(opt Options) config.CacheOptions {
+	caches := opt.Stack.Caches
+	if caches == nil {
+		return config.CacheOptions{}
+	}
+
+	cacheConfig := func(cache string, spec *lokiv1beta1.CacheSpec) *config.CacheConfig {
+		if spec == nil {
+			return nil
+		}
+
+		if spec.Managed {
+			svc := fqdn(serviceNameMemcached(opt.Name, cache), opt.Namespace)
+			return &config.CacheConfig{
+				Type:      lokiv1beta1.CacheMemcached,
+				Addresses: fmt.Sprintf("dnssrvnoa+_%s._tcp.%s", memcachedPortName, svc),
+			}
+		}
+
+		return &config.CacheConfig{
+			Type:      spec.Type,
+			Addresses: strings.Join(spec.Endpoints, ","),
+		}
 	}
+
+	return config.CacheOptions{
+		Chunks:       cacheConfig(cacheChunks, caches.Chunks),
+		IndexQueries: cacheConfig(cacheIndexQueries, caches.IndexQueries),
+		Results:      cacheConfig(cacheResults, caches.Results),
+	}
+}
+
+// indexGatewayAddress returns the index gateway GRPC address if the index gateway is enabled.
+func indexGatewayAddress(opt Options) config.Address {
+	if !indexGatewayEnabled(opt) {
+		return config.Address{}
+	}
+
+	return config.Address{
+		FQDN: fqdn(NewIndexGatewayGRPCService(opt).GetName(), opt.Namespace),
+		Port: grpcPort,
+	}
+}
+
+// rulerOptions returns the ruler configuration if the ruler component is enabled.
+func rulerOptions(opt Options) config.RulerOptions {
+	if !rulerEnabled(opt) {
+		return config.RulerOptions{}
+	}
+
+	ro := config.RulerOptions{
+		Enabled:               true,
+		RulesStorageDirectory: rulesStorageDirectory,
+		AlertManagerURL:       strings.Join(opt.Stack.Rules.AlertManagerEndpoints, ","),
+	}
+
+	if rw := opt.Stack.Rules.RemoteWrite; rw != nil {
+		ro.RemoteWriteURL = rw.URL
+	}
+
+	return ro
+}
+
+// retentionEnabled returns true if a retention period is defined globally or for any tenant.
+func retentionEnabled(limits *lokiv1beta1.LimitsSpec) bool {
+	if limits == nil {
+		return false
+	}
+
+	if limits.Global != nil && limits.Global.Retention != nil {
+		return true
+	}
+
+	for _, tenant := range limits.Tenants {
+		if tenant.Retention != nil {
+			return true
+		}
+	}
+
+	return false
 }
 
 func lokiConfigMapName(stackName string) string {
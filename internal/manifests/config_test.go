@@ -7,10 +7,13 @@ import (
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/utils/pointer"
 )
 
@@ -39,6 +42,220 @@ func TestConfigOptions_UserOptionsTakePrecedence(t *testing.T) {
 	assert.JSONEq(t, string(expected), string(actual))
 }
 
+func TestConfigOptions_RetentionEnabled(t *testing.T) {
+	type test struct {
+		name   string
+		limits *lokiv1beta1.LimitsSpec
+		want   bool
+	}
+	table := []test{
+		{
+			name: "no limits",
+		},
+		{
+			name: "no retention",
+			limits: &lokiv1beta1.LimitsSpec{
+				Global: &lokiv1beta1.LimitsTemplateSpec{},
+			},
+		},
+		{
+			name: "global retention",
+			limits: &lokiv1beta1.LimitsSpec{
+				Global: &lokiv1beta1.LimitsTemplateSpec{
+					Retention: &lokiv1beta1.RetentionLimitSpec{Days: 30},
+				},
+			},
+			want: true,
+		},
+		{
+			name: "tenant retention",
+			limits: &lokiv1beta1.LimitsSpec{
+				Tenants: map[string]lokiv1beta1.LimitsTemplateSpec{
+					"application": {
+						Retention: &lokiv1beta1.RetentionLimitSpec{Days: 7},
+					},
+				},
+			},
+			want: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := randomConfigOptions()
+			opts.Stack.Limits = tst.limits
+
+			res := manifests.ConfigOptions(opts)
+			require.Equal(t, tst.want, res.Retention.Enabled)
+		})
+	}
+}
+
+func TestConfigOptions_Caches(t *testing.T) {
+	opts := manifests.Options{
+		Name:      "lokistack-dev",
+		Namespace: "default",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXExtraSmall,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				QueryFrontend: &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+			},
+			Caches: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+				Results: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{"redis-0.cache.svc:6379", "redis-1.cache.svc:6379"},
+				},
+			},
+		},
+	}
+
+	res := manifests.ConfigOptions(opts)
+	require.Equal(t, &config.CacheConfig{
+		Type:      lokiv1beta1.CacheMemcached,
+		Addresses: "dnssrvnoa+_client._tcp.loki-memcached-chunks-lokistack-dev.default.svc.cluster.local",
+	}, res.Caches.Chunks)
+	require.Nil(t, res.Caches.IndexQueries)
+	require.Equal(t, &config.CacheConfig{
+		Type:      lokiv1beta1.CacheRedis,
+		Addresses: "redis-0.cache.svc:6379,redis-1.cache.svc:6379",
+	}, res.Caches.Results)
+}
+
+func TestConfigOptions_WriteAheadLog(t *testing.T) {
+	ceiling := resource.MustParse("2Gi")
+
+	type test struct {
+		name string
+		wal  *lokiv1beta1.WriteAheadLogSpec
+		want config.WriteAheadLog
+	}
+	table := []test{
+		{
+			name: "not configured",
+		},
+		{
+			name: "disabled",
+			wal:  &lokiv1beta1.WriteAheadLogSpec{},
+		},
+		{
+			name: "defaults",
+			wal:  &lokiv1beta1.WriteAheadLogSpec{Enabled: true},
+			want: config.WriteAheadLog{
+				Enabled:             true,
+				Directory:           "/tmp/loki/wal",
+				CheckpointDuration:  "5m",
+				ReplayMemoryCeiling: 3221225472,
+			},
+		},
+		{
+			name: "user settings",
+			wal: &lokiv1beta1.WriteAheadLogSpec{
+				Enabled:             true,
+				ReplayMemoryCeiling: &ceiling,
+				CheckpointDuration:  "10m",
+			},
+			want: config.WriteAheadLog{
+				Enabled:             true,
+				Directory:           "/tmp/loki/wal",
+				CheckpointDuration:  "10m",
+				ReplayMemoryCeiling: 2147483648,
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := manifests.Options{
+				Stack: lokiv1beta1.LokiStackSpec{
+					Template: &lokiv1beta1.LokiTemplateSpec{
+						QueryFrontend: &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+					},
+					WriteAheadLog: tst.wal,
+				},
+				ResourceRequirements: internal.ComponentResources{
+					Ingester: internal.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("4Gi"),
+						},
+					},
+				},
+			}
+
+			res := manifests.ConfigOptions(opts)
+			require.Equal(t, tst.want, res.WriteAheadLog)
+		})
+	}
+}
+
+func TestConfigOptions_QueryParallelism(t *testing.T) {
+	type test struct {
+		name          string
+		queryFrontend *lokiv1beta1.LokiComponentSpec
+		want          int32
+	}
+	table := []test{
+		{
+			name:          "fixed replicas",
+			queryFrontend: &lokiv1beta1.LokiComponentSpec{Replicas: 2},
+			want:          4,
+		},
+		{
+			name: "autoscaling",
+			queryFrontend: &lokiv1beta1.LokiComponentSpec{
+				Replicas: 2,
+				Autoscaling: &lokiv1beta1.AutoscalingSpec{
+					MinReplicas: 1,
+					MaxReplicas: 4,
+				},
+			},
+			want: 2,
+		},
+		{
+			name: "autoscaling beyond querier cpu",
+			queryFrontend: &lokiv1beta1.LokiComponentSpec{
+				Replicas: 2,
+				Autoscaling: &lokiv1beta1.AutoscalingSpec{
+					MinReplicas: 1,
+					MaxReplicas: 16,
+				},
+			},
+			want: 1,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := manifests.Options{
+				Stack: lokiv1beta1.LokiStackSpec{
+					Template: &lokiv1beta1.LokiTemplateSpec{
+						QueryFrontend: tst.queryFrontend,
+					},
+				},
+				ResourceRequirements: internal.ComponentResources{
+					Querier: internal.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU: resource.MustParse("8"),
+						},
+					},
+				},
+			}
+
+			res := manifests.ConfigOptions(opts)
+			require.Equal(t, tst.want, res.QueryParallelism.Value())
+		})
+	}
+}
+
 func randomConfigOptions() manifests.Options {
 	return manifests.Options{
 		Name:      uuid.New().String(),
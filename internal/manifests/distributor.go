@@ -5,12 +5,13 @@ import (
 	"path"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -30,16 +31,27 @@ func BuildDistributor(opts Options) ([]client.Object, error) {
 		}
 	}
 
-	return []client.Object{
+	if err := storage.ConfigureDeployment(deployment, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{
 		deployment,
 		NewDistributorGRPCService(opts),
 		NewDistributorHTTPService(opts),
-	}, nil
+	}
+
+	if autoscalingEnabled(opts.Stack.Template.Distributor) {
+		objs = append(objs, NewDistributorHorizontalPodAutoscaler(opts))
+	}
+
+	return objs, nil
 }
 
 // NewDistributorDeployment creates a deployment object for a distributor
 func NewDistributorDeployment(opts Options) *appsv1.Deployment {
 	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
 		Volumes: []corev1.Volume{
 			{
 				Name: configVolumeName,
@@ -135,15 +147,10 @@ func NewDistributorDeployment(opts Options) *appsv1.Deployment {
 		},
 	}
 
-	if opts.Stack.Template != nil && opts.Stack.Template.Distributor != nil {
-		podSpec.Tolerations = opts.Stack.Template.Distributor.Tolerations
-		podSpec.NodeSelector = opts.Stack.Template.Distributor.NodeSelector
-	}
-
 	l := ComponentLabels(LabelDistributorComponent, opts.Name)
-	a := commonAnnotations(opts.ConfigSHA1)
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
 
-	return &appsv1.Deployment{
+	dpl := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -153,7 +160,7 @@ func NewDistributorDeployment(opts Options) *appsv1.Deployment {
 			Labels: l,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: pointer.Int32Ptr(opts.Stack.Template.Distributor.Replicas),
+			Replicas: componentReplicas(opts.Stack.Template.Distributor),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels.Merge(l, GossipLabels()),
 			},
@@ -170,6 +177,13 @@ func NewDistributorDeployment(opts Options) *appsv1.Deployment {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&dpl.Spec.Template, opts.Stack.Template.Distributor)
+	}
+	configureZoneAwareness(&dpl.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return dpl
 }
 
 // NewDistributorGRPCService creates a k8s service for the distributor GRPC endpoint
@@ -230,6 +244,12 @@ func NewDistributorHTTPService(opts Options) *corev1.Service {
 	}
 }
 
+// NewDistributorHorizontalPodAutoscaler creates a horizontal pod autoscaler for the distributor deployment
+func NewDistributorHorizontalPodAutoscaler(opts Options) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	l := ComponentLabels(LabelDistributorComponent, opts.Name)
+	return newHorizontalPodAutoscaler(DistributorName(opts.Name), "Deployment", l, opts.Stack.Template.Distributor.Autoscaling)
+}
+
 func configureDistributorServiceMonitorPKI(deployment *appsv1.Deployment, stackName string) error {
 	serviceName := serviceNameDistributorHTTP(stackName)
 	return configureServiceMonitorPKI(&deployment.Spec.Template.Spec, serviceName)
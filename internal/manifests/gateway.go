@@ -11,6 +11,7 @@ import (
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/gateway"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,6 +41,10 @@ func BuildGateway(opts Options) ([]client.Object, error) {
 
 	objs := []client.Object{cm, dpl, svc, ing}
 
+	if gatewayAutoscalingEnabled(opts) {
+		objs = append(objs, NewGatewayHorizontalPodAutoscaler(opts))
+	}
+
 	if opts.Flags.EnableTLSServiceMonitorConfig {
 		serviceName := serviceNameGatewayHTTP(opts.Name)
 		if err := configureGatewayMetricsPKI(&dpl.Spec.Template.Spec, serviceName); err != nil {
@@ -177,9 +182,9 @@ func NewGatewayDeployment(opts Options, sha1C string) *appsv1.Deployment {
 	}
 
 	l := ComponentLabels(LabelGatewayComponent, opts.Name)
-	a := commonAnnotations(sha1C)
+	a := commonAnnotations(sha1C, "")
 
-	return &appsv1.Deployment{
+	dpl := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -189,7 +194,7 @@ func NewGatewayDeployment(opts Options, sha1C string) *appsv1.Deployment {
 			Labels: l,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: pointer.Int32Ptr(1),
+			Replicas: gatewayReplicas(opts),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: l,
 			},
@@ -206,6 +211,31 @@ func NewGatewayDeployment(opts Options, sha1C string) *appsv1.Deployment {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&dpl.Spec.Template, opts.Stack.Template.Gateway)
+	}
+	configureZoneAwareness(&dpl.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return dpl
+}
+
+// NewGatewayHorizontalPodAutoscaler creates a horizontal pod autoscaler for the lokiStack-gateway deployment
+func NewGatewayHorizontalPodAutoscaler(opts Options) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	l := ComponentLabels(LabelGatewayComponent, opts.Name)
+	return newHorizontalPodAutoscaler(GatewayName(opts.Name), "Deployment", l, opts.Stack.Template.Gateway.Autoscaling)
+}
+
+// gatewayReplicas returns a single replica or nil if the gateway is autoscaled.
+func gatewayReplicas(opts Options) *int32 {
+	if gatewayAutoscalingEnabled(opts) {
+		return nil
+	}
+	return pointer.Int32Ptr(1)
+}
+
+func gatewayAutoscalingEnabled(opts Options) bool {
+	return opts.Stack.Template != nil && autoscalingEnabled(opts.Stack.Template.Gateway)
 }
 
 // NewGatewayHTTPService creates a k8s service for the lokistack-gateway HTTP endpoint
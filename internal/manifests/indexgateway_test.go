@@ -0,0 +1,115 @@
+package manifests_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewIndexGatewayStatefulSet_SelectorMatchesLabels(t *testing.T) {
+	// You must set the .spec.selector field of a StatefulSet to match the labels of
+	// its .spec.template.metadata.labels. Prior to Kubernetes 1.8, the
+	// .spec.selector field was defaulted when omitted. In 1.8 and later versions,
+	// failing to specify a matching Pod Selector will result in a validation error
+	// during StatefulSet creation.
+	// See https://kubernetes.io/docs/concepts/workloads/controllers/statefulset/#pod-selector
+	sts := manifests.NewIndexGatewayStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+
+	l := sts.Spec.Template.GetObjectMeta().GetLabels()
+	for key, value := range sts.Spec.Selector.MatchLabels {
+		require.Contains(t, l, key)
+		require.Equal(t, l[key], value)
+	}
+}
+
+func TestNewIndexGatewayStatefulSet_HasTemplateConfigHashAnnotation(t *testing.T) {
+	ss := manifests.NewIndexGatewayStatefulSet(manifests.Options{
+		Name:       "abcd",
+		Namespace:  "efgh",
+		ConfigSHA1: "deadbeef",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+	expected := "loki.openshift.io/config-hash"
+	annotations := ss.Spec.Template.Annotations
+	require.Contains(t, annotations, expected)
+	require.Equal(t, annotations[expected], "deadbeef")
+}
+
+func TestBuildObsoleteIndexGateway(t *testing.T) {
+	type test struct {
+		name     string
+		template *lokiv1beta1.LokiTemplateSpec
+		flags    manifests.FeatureFlags
+		want     []string
+	}
+	table := []test{
+		{
+			name: "index gateway defined",
+			template: &lokiv1beta1.LokiTemplateSpec{
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+			},
+		},
+		{
+			name: "index gateway not defined",
+			want: []string{
+				"StatefulSet/loki-index-gateway-abcd",
+				"Service/loki-index-gateway-grpc-abcd",
+				"Service/loki-index-gateway-http-abcd",
+				"PodDisruptionBudget/loki-index-gateway-abcd",
+			},
+		},
+		{
+			name:     "index gateway not defined with service monitors",
+			template: &lokiv1beta1.LokiTemplateSpec{},
+			flags:    manifests.FeatureFlags{EnableServiceMonitors: true},
+			want: []string{
+				"StatefulSet/loki-index-gateway-abcd",
+				"Service/loki-index-gateway-grpc-abcd",
+				"Service/loki-index-gateway-http-abcd",
+				"PodDisruptionBudget/loki-index-gateway-abcd",
+				"ServiceMonitor/monitor-loki-index-gateway-abcd",
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs := manifests.BuildObsoleteIndexGateway(manifests.Options{
+				Name:      "abcd",
+				Namespace: "efgh",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Template: tst.template,
+				},
+				Flags: tst.flags,
+			})
+
+			var got []string
+			for _, obj := range objs {
+				got = append(got, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+			}
+			require.Equal(t, tst.want, got)
+		})
+	}
+}
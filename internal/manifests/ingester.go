@@ -5,6 +5,7 @@ import (
 	"path"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -24,6 +25,14 @@ func BuildIngester(opts Options) ([]client.Object, error) {
 		}
 	}
 
+	if err := storage.ConfigureStatefulSet(statefulSet, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
+	if opts.Stack.ZoneAwareness != nil {
+		configureIngesterAvailabilityZone(statefulSet, opts.Image)
+	}
+
 	return []client.Object{
 		statefulSet,
 		NewIngesterGRPCService(opts),
@@ -34,6 +43,7 @@ func BuildIngester(opts Options) ([]client.Object, error) {
 // NewIngesterStatefulSet creates a deployment object for an ingester
 func NewIngesterStatefulSet(opts Options) *appsv1.StatefulSet {
 	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
 		Volumes: []corev1.Volume{
 			{
 				Name: configVolumeName,
@@ -123,14 +133,9 @@ func NewIngesterStatefulSet(opts Options) *appsv1.StatefulSet {
 		},
 	}
 
-	if opts.Stack.Template != nil && opts.Stack.Template.Ingester != nil {
-		podSpec.Tolerations = opts.Stack.Template.Ingester.Tolerations
-		podSpec.NodeSelector = opts.Stack.Template.Ingester.NodeSelector
-	}
-
 	l := ComponentLabels(LabelIngesterComponent, opts.Name)
-	a := commonAnnotations(opts.ConfigSHA1)
-	return &appsv1.StatefulSet{
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
+	sts := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "StatefulSet",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -140,7 +145,12 @@ func NewIngesterStatefulSet(opts Options) *appsv1.StatefulSet {
 			Labels: l,
 		},
 		Spec: appsv1.StatefulSetSpec{
-			PodManagementPolicy:  appsv1.OrderedReadyPodManagement,
+			PodManagementPolicy: appsv1.OrderedReadyPodManagement,
+			// Pods are replaced by the operator one at a time after
+			// flushing their chunks and leaving the ring.
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type: appsv1.OnDeleteStatefulSetStrategyType,
+			},
 			RevisionHistoryLimit: pointer.Int32Ptr(10),
 			Replicas:             pointer.Int32Ptr(opts.Stack.Template.Ingester.Replicas),
 			Selector: &metav1.LabelSelector{
@@ -177,6 +187,13 @@ func NewIngesterStatefulSet(opts Options) *appsv1.StatefulSet {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&sts.Spec.Template, opts.Stack.Template.Ingester)
+	}
+	configureZoneAwareness(&sts.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return sts
 }
 
 // NewIngesterGRPCService creates a k8s service for the ingester GRPC endpoint
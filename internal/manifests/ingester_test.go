@@ -5,7 +5,10 @@ import (
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
 )
 
 func TestNewIngesterStatefulSet_HasTemplateConfigHashAnnotation(t *testing.T) {
@@ -29,6 +32,30 @@ func TestNewIngesterStatefulSet_HasTemplateConfigHashAnnotation(t *testing.T) {
 	require.Equal(t, annotations[expected], "deadbeef")
 }
 
+func TestNewIngesterStatefulSet_HasTemplateObjectStorageSecretHashAnnotation(t *testing.T) {
+	ss := manifests.NewIngesterStatefulSet(manifests.Options{
+		Name:       "abcd",
+		Namespace:  "efgh",
+		ConfigSHA1: "deadbeef",
+		ObjectStorage: storage.Options{
+			SecretSHA1: "cafebabe",
+		},
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+
+	expected := "loki.openshift.io/object-storage-secret-hash"
+	annotations := ss.Spec.Template.Annotations
+	require.Contains(t, annotations, expected)
+	require.Equal(t, annotations[expected], "cafebabe")
+}
+
 func TestNewIngesterStatefulSet_SelectorMatchesLabels(t *testing.T) {
 	// You must set the .spec.selector field of a StatefulSet to match the labels of
 	// its .spec.template.metadata.labels. Prior to Kubernetes 1.8, the
@@ -55,3 +82,20 @@ func TestNewIngesterStatefulSet_SelectorMatchesLabels(t *testing.T) {
 		require.Equal(t, l[key], value)
 	}
 }
+
+func TestNewIngesterStatefulSet_UpdatesOnDelete(t *testing.T) {
+	sts := manifests.NewIngesterStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ingester: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+
+	require.Equal(t, appsv1.OnDeleteStatefulSetStrategyType, sts.Spec.UpdateStrategy.Type)
+}
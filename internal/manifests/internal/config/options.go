@@ -4,6 +4,7 @@ import (
 	"math"
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 )
 
 // Options is used to render the loki-config.yaml file template
@@ -16,8 +17,14 @@ type Options struct {
 	GossipRing       Address
 	Querier          Address
 	StorageDirectory string
-	ObjectStorage    ObjectStorage
+	ObjectStorage    storage.Options
 	QueryParallelism Parallelism
+	Retention        RetentionOptions
+	Ruler            RulerOptions
+	// IndexGateway is empty if the index gateway is disabled.
+	IndexGateway  Address
+	Caches        CacheOptions
+	WriteAheadLog WriteAheadLog
 }
 
 // Address FQDN and port for a k8s service.
@@ -28,13 +35,44 @@ type Address struct {
 	Port int
 }
 
-// ObjectStorage for storage config.
-type ObjectStorage struct {
-	Endpoint        string
-	Region          string
-	Buckets         string
-	AccessKeyID     string
-	AccessKeySecret string
+// RetentionOptions configures the compactor to delete
+// logs after their retention period.
+type RetentionOptions struct {
+	Enabled bool
+}
+
+// RulerOptions configures the ruler to evaluate the alerting
+// and recording rules from the local rules directory.
+type RulerOptions struct {
+	Enabled               bool
+	RulesStorageDirectory string
+	// AlertManagerURL is a comma-separated list of Alertmanager URLs.
+	AlertManagerURL string
+	RemoteWriteURL  string
+}
+
+// WriteAheadLog configures the write-ahead log of the ingesters.
+type WriteAheadLog struct {
+	Enabled            bool
+	Directory          string
+	CheckpointDuration string
+	// ReplayMemoryCeiling is the threshold in bytes.
+	ReplayMemoryCeiling int64
+}
+
+// CacheOptions configures the chunks, index queries
+// and results caches. A nil cache is not configured.
+type CacheOptions struct {
+	Chunks       *CacheConfig
+	IndexQueries *CacheConfig
+	Results      *CacheConfig
+}
+
+// CacheConfig configures a memcached or Redis cache.
+type CacheConfig struct {
+	Type lokiv1beta1.CacheType
+	// Addresses is a comma-separated list of cache addresses.
+	Addresses string
 }
 
 // Parallelism for query processing parallelism
@@ -46,7 +84,11 @@ type Parallelism struct {
 
 // Value calculates the floor of the division of
 // querier cpu limits to the query frontend replicas
-// available.
+// available, but at least 1.
 func (p Parallelism) Value() int32 {
-	return int32(math.Floor(float64(p.QuerierCPULimits) / float64(p.QueryFrontendReplicas)))
+	v := int32(math.Floor(float64(p.QuerierCPULimits) / float64(p.QueryFrontendReplicas)))
+	if v < 1 {
+		return 1
+	}
+	return v
 }
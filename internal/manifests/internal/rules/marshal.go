@@ -0,0 +1,43 @@
+package rules
+
+import (
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+type alertingRuleSpec struct {
+	Groups []*lokiv1beta1.AlertingRuleGroup `json:"groups"`
+}
+
+type recordingRuleSpec struct {
+	Groups []*lokiv1beta1.RecordingRuleGroup `json:"groups"`
+}
+
+// MarshalAlertingRule returns the Loki ruler file content for the alerting rule groups.
+func MarshalAlertingRule(a lokiv1beta1.AlertingRule) (string, error) {
+	ar := alertingRuleSpec{
+		Groups: a.Spec.Groups,
+	}
+
+	content, err := yaml.Marshal(ar)
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to marshal alerting rule", "name", a.Name)
+	}
+
+	return string(content), nil
+}
+
+// MarshalRecordingRule returns the Loki ruler file content for the recording rule groups.
+func MarshalRecordingRule(r lokiv1beta1.RecordingRule) (string, error) {
+	rr := recordingRuleSpec{
+		Groups: r.Spec.Groups,
+	}
+
+	content, err := yaml.Marshal(rr)
+	if err != nil {
+		return "", kverrors.Wrap(err, "failed to marshal recording rule", "name", r.Name)
+	}
+
+	return string(content), nil
+}
@@ -0,0 +1,102 @@
+package rules_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal/rules"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalAlertingRule(t *testing.T) {
+	expCfg := `
+groups:
+- interval: 10m
+  limit: 2
+  name: alerts-1
+  rules:
+  - alert: HighPercentageErrors
+    annotations:
+      summary: High request error rate
+    expr: |
+      sum(rate({app="foo", env="production"} |= "error" [5m])) by (job)
+        /
+      sum(rate({app="foo", env="production"}[5m])) by (job)
+        > 0.05
+    for: 10m
+    labels:
+      severity: page
+`
+
+	a := lokiv1beta1.AlertingRule{
+		Spec: lokiv1beta1.AlertingRuleSpec{
+			TenantID: "application",
+			Groups: []*lokiv1beta1.AlertingRuleGroup{
+				{
+					Name:     "alerts-1",
+					Interval: "10m",
+					Limit:    2,
+					Rules: []*lokiv1beta1.AlertingRuleGroupSpec{
+						{
+							Alert: "HighPercentageErrors",
+							Expr: `sum(rate({app="foo", env="production"} |= "error" [5m])) by (job)
+  /
+sum(rate({app="foo", env="production"}[5m])) by (job)
+  > 0.05
+`,
+							For: "10m",
+							Labels: map[string]string{
+								"severity": "page",
+							},
+							Annotations: map[string]string{
+								"summary": "High request error rate",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg, err := rules.MarshalAlertingRule(a)
+	require.NoError(t, err)
+	require.YAMLEq(t, expCfg, cfg)
+}
+
+func TestMarshalRecordingRule(t *testing.T) {
+	expCfg := `
+groups:
+- interval: 1m
+  name: recordings-1
+  rules:
+  - expr: sum(rate({container="nginx"}[1m]))
+    labels:
+      cluster: us-central1
+    record: nginx:requests:rate1m
+`
+
+	r := lokiv1beta1.RecordingRule{
+		Spec: lokiv1beta1.RecordingRuleSpec{
+			TenantID: "application",
+			Groups: []*lokiv1beta1.RecordingRuleGroup{
+				{
+					Name:     "recordings-1",
+					Interval: "1m",
+					Rules: []*lokiv1beta1.RecordingRuleGroupSpec{
+						{
+							Record: "nginx:requests:rate1m",
+							Expr:   `sum(rate({container="nginx"}[1m]))`,
+							Labels: map[string]string{
+								"cluster": "us-central1",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	cfg, err := rules.MarshalRecordingRule(r)
+	require.NoError(t, err)
+	require.YAMLEq(t, expCfg, cfg)
+}
@@ -11,10 +11,15 @@ type ComponentResources struct {
 	Querier   ResourceRequirements
 	Ingester  ResourceRequirements
 	Compactor ResourceRequirements
+	Ruler     ResourceRequirements
+	// IndexGateway is only used if the index gateway is enabled
+	IndexGateway ResourceRequirements
 	// these two don't need a PVCSize
 	Distributor   corev1.ResourceRequirements
 	QueryFrontend corev1.ResourceRequirements
 	Gateway       corev1.ResourceRequirements
+	// Memcached is only used for caches managed by the operator
+	Memcached corev1.ResourceRequirements
 }
 
 // ResourceRequirements sets CPU, Memory, and PVC requirements for a component
@@ -60,12 +65,35 @@ var ResourceRequirementsTable = map[lokiv1beta1.LokiStackSizeType]ComponentResou
 				corev1.ResourceMemory: resource.MustParse("1Gi"),
 			},
 		},
+		Ruler: ResourceRequirements{
+			PVCSize: resource.MustParse("1Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("250m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
+		IndexGateway: ResourceRequirements{
+			PVCSize: resource.MustParse("1Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
 		Gateway: corev1.ResourceRequirements{
 			Requests: map[corev1.ResourceName]resource.Quantity{
 				corev1.ResourceCPU:    resource.MustParse("100m"),
 				corev1.ResourceMemory: resource.MustParse("256Mi"),
 			},
 		},
+		Memcached: corev1.ResourceRequirements{
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+			Limits: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		},
 	},
 	lokiv1beta1.SizeOneXSmall: {
 		Querier: ResourceRequirements{
@@ -101,12 +129,35 @@ var ResourceRequirementsTable = map[lokiv1beta1.LokiStackSizeType]ComponentResou
 				corev1.ResourceMemory: resource.MustParse("4Gi"),
 			},
 		},
+		Ruler: ResourceRequirements{
+			PVCSize: resource.MustParse("10Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+		IndexGateway: ResourceRequirements{
+			PVCSize: resource.MustParse("50Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
 		Gateway: corev1.ResourceRequirements{
 			Requests: map[corev1.ResourceName]resource.Quantity{
 				corev1.ResourceCPU:    resource.MustParse("1"),
 				corev1.ResourceMemory: resource.MustParse("1Gi"),
 			},
 		},
+		Memcached: corev1.ResourceRequirements{
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+			Limits: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceMemory: resource.MustParse("4Gi"),
+			},
+		},
 	},
 	lokiv1beta1.SizeOneXMedium: {
 		Querier: ResourceRequirements{
@@ -142,15 +193,54 @@ var ResourceRequirementsTable = map[lokiv1beta1.LokiStackSizeType]ComponentResou
 				corev1.ResourceMemory: resource.MustParse("4Gi"),
 			},
 		},
+		Ruler: ResourceRequirements{
+			PVCSize: resource.MustParse("10Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
+		IndexGateway: ResourceRequirements{
+			PVCSize: resource.MustParse("150Gi"),
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("500m"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+		},
 		Gateway: corev1.ResourceRequirements{
 			Requests: map[corev1.ResourceName]resource.Quantity{
 				corev1.ResourceCPU:    resource.MustParse("1"),
 				corev1.ResourceMemory: resource.MustParse("1Gi"),
 			},
 		},
+		Memcached: corev1.ResourceRequirements{
+			Requests: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+			Limits: map[corev1.ResourceName]resource.Quantity{
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+			},
+		},
 	},
 }
 
+// MemcachedReplicasTable defines the default number of replicas of each managed cache for each size
+var MemcachedReplicasTable = map[lokiv1beta1.LokiStackSizeType]int32{
+	lokiv1beta1.SizeOneXExtraSmall: 1,
+	lokiv1beta1.SizeOneXSmall:      2,
+	lokiv1beta1.SizeOneXMedium:     3,
+}
+
+// WriteAheadLogPVCSizeTable defines the default ingester PVC size for each size with the
+// write-ahead log enabled. Stacks without the write-ahead log keep the smaller PVC size
+// of the ResourceRequirementsTable, so that their volumes are not expanded on upgrade.
+var WriteAheadLogPVCSizeTable = map[lokiv1beta1.LokiStackSizeType]resource.Quantity{
+	lokiv1beta1.SizeOneXExtraSmall: resource.MustParse("10Gi"),
+	lokiv1beta1.SizeOneXSmall:      resource.MustParse("50Gi"),
+	lokiv1beta1.SizeOneXMedium:     resource.MustParse("50Gi"),
+}
+
 // StackSizeTable defines the default configurations for each size
 var StackSizeTable = map[lokiv1beta1.LokiStackSizeType]lokiv1beta1.LokiStackSpec{
 
@@ -192,6 +282,9 @@ var StackSizeTable = map[lokiv1beta1.LokiStackSizeType]lokiv1beta1.LokiStackSpec
 			QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 1,
 			},
+			Ruler: &lokiv1beta1.LokiComponentSpec{
+				Replicas: 1,
+			},
 			Gateway: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 2,
 			},
@@ -238,6 +331,9 @@ var StackSizeTable = map[lokiv1beta1.LokiStackSizeType]lokiv1beta1.LokiStackSpec
 			QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 2,
 			},
+			Ruler: &lokiv1beta1.LokiComponentSpec{
+				Replicas: 2,
+			},
 			Gateway: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 2,
 			},
@@ -284,6 +380,9 @@ var StackSizeTable = map[lokiv1beta1.LokiStackSizeType]lokiv1beta1.LokiStackSpec
 			QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 2,
 			},
+			Ruler: &lokiv1beta1.LokiComponentSpec{
+				Replicas: 2,
+			},
 			Gateway: &lokiv1beta1.LokiComponentSpec{
 				Replicas: 2,
 			},
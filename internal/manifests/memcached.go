@@ -0,0 +1,227 @@
+package manifests
+
+import (
+	"fmt"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	cacheChunks       = "chunks"
+	cacheIndexQueries = "index-queries"
+	cacheResults      = "results"
+
+	// memcachedMaxItemSize must fit the largest chunks flushed by the ingesters.
+	memcachedMaxItemSize = "5m"
+)
+
+// BuildMemcached builds the k8s objects required to run a memcached
+// statefulset for each cache managed by the operator.
+func BuildMemcached(opts Options) []client.Object {
+	var objs []client.Object
+	for _, cache := range managedCaches(opts) {
+		objs = append(objs,
+			NewMemcachedStatefulSet(opts, cache),
+			NewMemcachedService(opts, cache),
+		)
+	}
+	return objs
+}
+
+// BuildObsoleteMemcached returns the k8s objects of the memcached caches to delete
+// because they are no longer managed by the operator, e.g. after switching a cache
+// to external endpoints or removing it.
+func BuildObsoleteMemcached(opts Options) []client.Object {
+	managed := make(map[string]bool)
+	for _, cache := range managedCaches(opts) {
+		managed[cache] = true
+	}
+
+	var objs []client.Object
+	for _, cache := range []string{cacheChunks, cacheIndexQueries, cacheResults} {
+		if managed[cache] {
+			continue
+		}
+
+		objs = append(objs,
+			&appsv1.StatefulSet{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "StatefulSet",
+					APIVersion: appsv1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: MemcachedName(opts.Name, cache),
+				},
+			},
+			&corev1.Service{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "Service",
+					APIVersion: corev1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: serviceNameMemcached(opts.Name, cache),
+				},
+			},
+			&policyv1.PodDisruptionBudget{
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "PodDisruptionBudget",
+					APIVersion: policyv1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: MemcachedName(opts.Name, cache),
+				},
+			},
+		)
+	}
+	return objs
+}
+
+// NewMemcachedStatefulSet creates a statefulset object for a managed memcached cache.
+func NewMemcachedStatefulSet(opts Options, cache string) *appsv1.StatefulSet {
+	resources := opts.ResourceRequirements.Memcached
+
+	// Leave some headroom to the memory limit for connection buffers.
+	memoryMB := resources.Limits.Memory().Value() / 1024 / 1024 * 9 / 10
+
+	podSpec := corev1.PodSpec{
+		Containers: []corev1.Container{
+			{
+				Image:     opts.MemcachedImage,
+				Name:      "memcached",
+				Resources: resources,
+				Args: []string{
+					fmt.Sprintf("--memory-limit=%d", memoryMB),
+					fmt.Sprintf("--max-item-size=%s", memcachedMaxItemSize),
+					"--conn-limit=1024",
+				},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(memcachedPort),
+						},
+					},
+					PeriodSeconds:       10,
+					InitialDelaySeconds: 5,
+					TimeoutSeconds:      1,
+					SuccessThreshold:    1,
+					FailureThreshold:    3,
+				},
+				LivenessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{
+							Port: intstr.FromInt(memcachedPort),
+						},
+					},
+					TimeoutSeconds:   2,
+					PeriodSeconds:    30,
+					FailureThreshold: 10,
+					SuccessThreshold: 1,
+				},
+				Ports: []corev1.ContainerPort{
+					{
+						Name:          memcachedPortName,
+						ContainerPort: memcachedPort,
+						Protocol:      protocolTCP,
+					},
+				},
+				TerminationMessagePath:   "/dev/termination-log",
+				TerminationMessagePolicy: "File",
+				ImagePullPolicy:          "IfNotPresent",
+			},
+		},
+	}
+
+	l := ComponentLabels(memcachedComponent(cache), opts.Name)
+	configureZoneAwareness(&podSpec, l, opts.Stack.ZoneAwareness)
+	return &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   MemcachedName(opts.Name, cache),
+			Labels: l,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			ServiceName:          serviceNameMemcached(opts.Name, cache),
+			PodManagementPolicy:  appsv1.ParallelPodManagement,
+			RevisionHistoryLimit: pointer.Int32Ptr(10),
+			Replicas:             pointer.Int32Ptr(internal.MemcachedReplicasTable[opts.Stack.Size]),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: l,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   MemcachedName(opts.Name, cache),
+					Labels: l,
+				},
+				Spec: podSpec,
+			},
+		},
+	}
+}
+
+// NewMemcachedService creates a headless k8s service for a managed memcached cache.
+// The Loki components discover the memcached pods by its SRV records.
+func NewMemcachedService(opts Options, cache string) *corev1.Service {
+	l := ComponentLabels(memcachedComponent(cache), opts.Name)
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   serviceNameMemcached(opts.Name, cache),
+			Labels: l,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "None",
+			Ports: []corev1.ServicePort{
+				{
+					Name:       memcachedPortName,
+					Port:       memcachedPort,
+					Protocol:   protocolTCP,
+					TargetPort: intstr.IntOrString{IntVal: memcachedPort},
+				},
+			},
+			Selector: l,
+		},
+	}
+}
+
+// managedCaches returns the names of the caches deployed by the operator.
+func managedCaches(opts Options) []string {
+	caches := opts.Stack.Caches
+	if caches == nil {
+		return nil
+	}
+
+	var names []string
+	for _, c := range []struct {
+		name string
+		spec *lokiv1beta1.CacheSpec
+	}{
+		{name: cacheChunks, spec: caches.Chunks},
+		{name: cacheIndexQueries, spec: caches.IndexQueries},
+		{name: cacheResults, spec: caches.Results},
+	} {
+		if c.spec != nil && c.spec.Managed {
+			names = append(names, c.name)
+		}
+	}
+	return names
+}
+
+func memcachedComponent(cache string) string {
+	return fmt.Sprintf("memcached-%s", cache)
+}
@@ -0,0 +1,113 @@
+package manifests_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMemcachedStatefulSet_SelectorMatchesLabels(t *testing.T) {
+	sts := manifests.NewMemcachedStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+		},
+		ResourceRequirements: internal.ResourceRequirementsTable[lokiv1beta1.SizeOneXSmall],
+	}, "chunks")
+
+	l := sts.Spec.Template.GetObjectMeta().GetLabels()
+	for key, value := range sts.Spec.Selector.MatchLabels {
+		require.Contains(t, l, key)
+		require.Equal(t, l[key], value)
+	}
+}
+
+func TestNewMemcachedStatefulSet_SizedByStackSize(t *testing.T) {
+	sts := manifests.NewMemcachedStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+		},
+		ResourceRequirements: internal.ResourceRequirementsTable[lokiv1beta1.SizeOneXSmall],
+	}, "chunks")
+
+	require.Equal(t, "loki-memcached-chunks-abcd", sts.Name)
+	require.EqualValues(t, 2, *sts.Spec.Replicas)
+
+	// 90% of the 4Gi memory limit
+	require.Contains(t, sts.Spec.Template.Spec.Containers[0].Args, "--memory-limit=3686")
+}
+
+func TestBuildMemcached_OnlyManagedCaches(t *testing.T) {
+	objs := manifests.BuildMemcached(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size: lokiv1beta1.SizeOneXSmall,
+			Caches: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+				IndexQueries: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{"redis.cache.svc:6379"},
+				},
+				Results: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+			},
+		},
+	})
+
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+	}
+
+	require.Equal(t, []string{
+		"StatefulSet/loki-memcached-chunks-abcd",
+		"Service/loki-memcached-chunks-abcd",
+		"StatefulSet/loki-memcached-results-abcd",
+		"Service/loki-memcached-results-abcd",
+	}, names)
+}
+
+func TestBuildObsoleteMemcached_OnlyUnmanagedCaches(t *testing.T) {
+	objs := manifests.BuildObsoleteMemcached(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Caches: &lokiv1beta1.CachesSpec{
+				Chunks: &lokiv1beta1.CacheSpec{
+					Type:    lokiv1beta1.CacheMemcached,
+					Managed: true,
+				},
+				IndexQueries: &lokiv1beta1.CacheSpec{
+					Type:      lokiv1beta1.CacheRedis,
+					Endpoints: []string{"redis.cache.svc:6379"},
+				},
+			},
+		},
+	})
+
+	var names []string
+	for _, obj := range objs {
+		names = append(names, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+	}
+
+	require.Equal(t, []string{
+		"StatefulSet/loki-memcached-index-queries-abcd",
+		"Service/loki-memcached-index-queries-abcd",
+		"PodDisruptionBudget/loki-memcached-index-queries-abcd",
+		"StatefulSet/loki-memcached-results-abcd",
+		"Service/loki-memcached-results-abcd",
+		"PodDisruptionBudget/loki-memcached-results-abcd",
+	}, names)
+}
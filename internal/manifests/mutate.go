@@ -10,8 +10,10 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -25,6 +27,8 @@ import (
 // - Deployment
 // - StatefulSet
 // - ServiceMonitor
+// - HorizontalPodAutoscaler
+// - PodDisruptionBudget
 func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 	return func() error {
 		existingAnnotations := existing.GetAnnotations()
@@ -76,6 +80,16 @@ func MutateFuncFor(existing, desired client.Object) controllerutil.MutateFn {
 			wantSvcMonitor := desired.(*monitoringv1.ServiceMonitor)
 			mutateServiceMonitor(svcMonitor, wantSvcMonitor)
 
+		case *autoscalingv2beta2.HorizontalPodAutoscaler:
+			hpa := existing.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+			wantHpa := desired.(*autoscalingv2beta2.HorizontalPodAutoscaler)
+			mutateHorizontalPodAutoscaler(hpa, wantHpa)
+
+		case *policyv1.PodDisruptionBudget:
+			pdb := existing.(*policyv1.PodDisruptionBudget)
+			wantPdb := desired.(*policyv1.PodDisruptionBudget)
+			mutatePodDisruptionBudget(pdb, wantPdb)
+
 		case *networkingv1.Ingress:
 			ing := existing.(*networkingv1.Ingress)
 			wantIng := desired.(*networkingv1.Ingress)
@@ -134,7 +148,10 @@ func mutateDeployment(existing, desired *appsv1.Deployment) {
 	if existing.CreationTimestamp.IsZero() {
 		mergeWithOverride(existing.Spec.Selector, desired.Spec.Selector)
 	}
-	existing.Spec.Replicas = desired.Spec.Replicas
+	// Replicas are nil if managed by a HorizontalPodAutoscaler
+	if desired.Spec.Replicas != nil {
+		existing.Spec.Replicas = desired.Spec.Replicas
+	}
 	mergeWithOverride(&existing.Spec.Template, desired.Spec.Template)
 	mergeWithOverride(&existing.Spec.Strategy, desired.Spec.Strategy)
 }
@@ -146,12 +163,23 @@ func mutateStatefulSet(existing, desired *appsv1.StatefulSet) {
 		existing.Spec.Selector = desired.Spec.Selector
 	}
 	existing.Spec.PodManagementPolicy = desired.Spec.PodManagementPolicy
-	existing.Spec.Replicas = desired.Spec.Replicas
+	// Keep the defaulted update strategy unless a custom one is desired
+	if desired.Spec.UpdateStrategy.Type != "" {
+		existing.Spec.UpdateStrategy = desired.Spec.UpdateStrategy
+	}
+	// Replicas are nil if managed by a HorizontalPodAutoscaler
+	if desired.Spec.Replicas != nil {
+		existing.Spec.Replicas = desired.Spec.Replicas
+	}
 	mergeWithOverride(&existing.Spec.Template, desired.Spec.Template)
-	for i := range existing.Spec.VolumeClaimTemplates {
-		existing.Spec.VolumeClaimTemplates[i].TypeMeta = desired.Spec.VolumeClaimTemplates[i].TypeMeta
-		existing.Spec.VolumeClaimTemplates[i].ObjectMeta = desired.Spec.VolumeClaimTemplates[i].ObjectMeta
-		existing.Spec.VolumeClaimTemplates[i].Spec = desired.Spec.VolumeClaimTemplates[i].Spec
+	// StatefulSet volume claim templates are immutable, so changed PVC
+	// sizes only apply to new objects
+	if existing.CreationTimestamp.IsZero() {
+		for i := range existing.Spec.VolumeClaimTemplates {
+			existing.Spec.VolumeClaimTemplates[i].TypeMeta = desired.Spec.VolumeClaimTemplates[i].TypeMeta
+			existing.Spec.VolumeClaimTemplates[i].ObjectMeta = desired.Spec.VolumeClaimTemplates[i].ObjectMeta
+			existing.Spec.VolumeClaimTemplates[i].Spec = desired.Spec.VolumeClaimTemplates[i].Spec
+		}
 	}
 }
 
@@ -173,3 +201,16 @@ func mutateRoute(existing, desired *routev1.Route) {
 	existing.Labels = desired.Labels
 	existing.Spec = desired.Spec
 }
+
+func mutateHorizontalPodAutoscaler(existing, desired *autoscalingv2beta2.HorizontalPodAutoscaler) {
+	existing.Spec.ScaleTargetRef = desired.Spec.ScaleTargetRef
+	existing.Spec.MinReplicas = desired.Spec.MinReplicas
+	existing.Spec.MaxReplicas = desired.Spec.MaxReplicas
+	existing.Spec.Metrics = desired.Spec.Metrics
+}
+
+func mutatePodDisruptionBudget(existing, desired *policyv1.PodDisruptionBudget) {
+	existing.Spec.Selector = desired.Spec.Selector
+	existing.Spec.MinAvailable = desired.Spec.MinAvailable
+	existing.Spec.MaxUnavailable = desired.Spec.MaxUnavailable
+}
@@ -10,9 +10,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
@@ -937,3 +940,203 @@ func TestGetMutateFunc_MutateRoute(t *testing.T) {
 	require.Exactly(t, got.Annotations, want.Annotations)
 	require.Exactly(t, got.Spec, want.Spec)
 }
+
+func TestGetMutateFunc_MutateAutoscaledReplicas(t *testing.T) {
+	t.Run("deployment", func(t *testing.T) {
+		got := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Spec: appsv1.DeploymentSpec{
+				Replicas: pointer.Int32Ptr(5),
+			},
+		}
+		want := &appsv1.Deployment{}
+
+		f := manifests.MutateFuncFor(got, want)
+		err := f()
+		require.NoError(t, err)
+
+		// Replicas managed by a HorizontalPodAutoscaler are kept
+		require.Equal(t, pointer.Int32Ptr(5), got.Spec.Replicas)
+	})
+
+	t.Run("statefulset", func(t *testing.T) {
+		got := &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: pointer.Int32Ptr(5),
+			},
+		}
+		want := &appsv1.StatefulSet{}
+
+		f := manifests.MutateFuncFor(got, want)
+		err := f()
+		require.NoError(t, err)
+
+		// Replicas managed by a HorizontalPodAutoscaler are kept
+		require.Equal(t, pointer.Int32Ptr(5), got.Spec.Replicas)
+	})
+}
+
+func TestGetMutateFunc_MutateHorizontalPodAutoscaler(t *testing.T) {
+	got := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			MinReplicas: pointer.Int32Ptr(1),
+			MaxReplicas: 2,
+		},
+	}
+
+	want := &autoscalingv2beta2.HorizontalPodAutoscaler{
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "a-deployment",
+			},
+			MinReplicas: pointer.Int32Ptr(2),
+			MaxReplicas: 4,
+			Metrics: []autoscalingv2beta2.MetricSpec{
+				{
+					Type: autoscalingv2beta2.ResourceMetricSourceType,
+					Resource: &autoscalingv2beta2.ResourceMetricSource{
+						Name: corev1.ResourceCPU,
+						Target: autoscalingv2beta2.MetricTarget{
+							Type:               autoscalingv2beta2.UtilizationMetricType,
+							AverageUtilization: pointer.Int32Ptr(60),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	f := manifests.MutateFuncFor(got, want)
+	err := f()
+	require.NoError(t, err)
+
+	// Partial mutation checks
+	require.Exactly(t, got.Spec, want.Spec)
+}
+
+func TestGetMutateFunc_MutatePodDisruptionBudget(t *testing.T) {
+	one := intstr.FromInt(1)
+	two := intstr.FromInt(2)
+
+	got := &policyv1.PodDisruptionBudget{
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"test": "test",
+				},
+			},
+			MaxUnavailable: &one,
+		},
+	}
+
+	want := &policyv1.PodDisruptionBudget{
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"test":  "test",
+					"other": "label",
+				},
+			},
+			MaxUnavailable: &two,
+		},
+	}
+
+	f := manifests.MutateFuncFor(got, want)
+	err := f()
+	require.NoError(t, err)
+
+	// Partial mutation checks
+	require.Exactly(t, got.Spec, want.Spec)
+}
+
+func TestGetMutateFunc_MutateStatefulSetKeepsVolumeClaimTemplates(t *testing.T) {
+	newClaims := func(size string) []corev1.PersistentVolumeClaim {
+		return []corev1.PersistentVolumeClaim{
+			{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "storage",
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse(size),
+						},
+					},
+				},
+			},
+		}
+	}
+
+	got := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: newClaims("10Gi"),
+		},
+	}
+
+	want := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			VolumeClaimTemplates: newClaims("20Gi"),
+		},
+	}
+
+	f := manifests.MutateFuncFor(got, want)
+	err := f()
+	require.NoError(t, err)
+
+	// Volume claim templates are immutable on existing statefulsets
+	require.Equal(t, newClaims("10Gi"), got.Spec.VolumeClaimTemplates)
+}
+
+func TestGetMutateFunc_MutateStatefulSetUpdateStrategy(t *testing.T) {
+	rollingUpdate := appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: pointer.Int32Ptr(0),
+		},
+	}
+
+	table := []struct {
+		name    string
+		desired appsv1.StatefulSetUpdateStrategy
+		want    appsv1.StatefulSetUpdateStrategy
+	}{
+		{
+			name: "keep defaulted strategy",
+			want: rollingUpdate,
+		},
+		{
+			name:    "set desired strategy",
+			desired: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+			want:    appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()},
+				Spec: appsv1.StatefulSetSpec{
+					UpdateStrategy: *rollingUpdate.DeepCopy(),
+				},
+			}
+
+			want := &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					UpdateStrategy: tst.desired,
+				},
+			}
+
+			f := manifests.MutateFuncFor(got, want)
+			err := f()
+			require.NoError(t, err)
+
+			require.Equal(t, tst.want, got.Spec.UpdateStrategy)
+		})
+	}
+}
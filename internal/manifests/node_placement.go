@@ -0,0 +1,46 @@
+package manifests
+
+import (
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// configurePodTemplate applies the node placement, scheduling and security
+// settings of a component's template spec to the component pod template.
+func configurePodTemplate(tpl *corev1.PodTemplateSpec, spec *lokiv1beta1.LokiComponentSpec) {
+	if spec == nil {
+		return
+	}
+
+	// Labels and annotations of the operator are required for selectors and rollouts.
+	if len(spec.PodLabels) > 0 {
+		tpl.Labels = labels.Merge(spec.PodLabels, tpl.Labels)
+	}
+	if len(spec.PodAnnotations) > 0 {
+		tpl.Annotations = labels.Merge(spec.PodAnnotations, tpl.Annotations)
+	}
+
+	podSpec := &tpl.Spec
+	podSpec.Tolerations = spec.Tolerations
+	podSpec.NodeSelector = spec.NodeSelector
+	podSpec.PriorityClassName = spec.PriorityClassName
+	podSpec.ImagePullSecrets = spec.ImagePullSecrets
+
+	if spec.Affinity != nil {
+		podSpec.Affinity = spec.Affinity.DeepCopy()
+	}
+
+	if spec.PodSecurityContext != nil {
+		podSpec.SecurityContext = spec.PodSecurityContext.DeepCopy()
+	}
+
+	if spec.SecurityContext != nil {
+		for i := range podSpec.InitContainers {
+			podSpec.InitContainers[i].SecurityContext = spec.SecurityContext.DeepCopy()
+		}
+		for i := range podSpec.Containers {
+			podSpec.Containers[i].SecurityContext = spec.SecurityContext.DeepCopy()
+		}
+	}
+}
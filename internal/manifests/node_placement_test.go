@@ -4,8 +4,10 @@ import (
 	"testing"
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 )
 
 func TestTolerationsAreSetForEachComponent(t *testing.T) {
@@ -38,9 +40,21 @@ func TestTolerationsAreSetForEachComponent(t *testing.T) {
 					Tolerations: tolerations,
 					Replicas:    1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Tolerations: tolerations,
+					Replicas:    1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Tolerations: tolerations,
+					Replicas:    1,
+				},
+				Gateway: &lokiv1beta1.LokiComponentSpec{
+					Tolerations: tolerations,
+					Replicas:    1,
+				},
 			},
 		},
-		ObjectStorage: ObjectStorage{},
+		ObjectStorage: storage.Options{},
 	}
 
 	optsWithoutTolerations := Options{
@@ -61,9 +75,18 @@ func TestTolerationsAreSetForEachComponent(t *testing.T) {
 				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				Gateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
 			},
 		},
-		ObjectStorage: ObjectStorage{},
+		ObjectStorage: storage.Options{},
 	}
 
 	t.Run("distributor", func(t *testing.T) {
@@ -90,6 +113,21 @@ func TestTolerationsAreSetForEachComponent(t *testing.T) {
 		assert.Equal(t, tolerations, NewCompactorStatefulSet(optsWithTolerations).Spec.Template.Spec.Tolerations)
 		assert.Empty(t, NewCompactorStatefulSet(optsWithoutTolerations).Spec.Template.Spec.Tolerations)
 	})
+
+	t.Run("ruler", func(t *testing.T) {
+		assert.Equal(t, tolerations, NewRulerStatefulSet(optsWithTolerations).Spec.Template.Spec.Tolerations)
+		assert.Empty(t, NewRulerStatefulSet(optsWithoutTolerations).Spec.Template.Spec.Tolerations)
+	})
+
+	t.Run("index gateway", func(t *testing.T) {
+		assert.Equal(t, tolerations, NewIndexGatewayStatefulSet(optsWithTolerations).Spec.Template.Spec.Tolerations)
+		assert.Empty(t, NewIndexGatewayStatefulSet(optsWithoutTolerations).Spec.Template.Spec.Tolerations)
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		assert.Equal(t, tolerations, NewGatewayDeployment(optsWithTolerations, "").Spec.Template.Spec.Tolerations)
+		assert.Empty(t, NewGatewayDeployment(optsWithoutTolerations, "").Spec.Template.Spec.Tolerations)
+	})
 }
 
 func TestNodeSelectorsAreSetForEachComponent(t *testing.T) {
@@ -117,9 +155,21 @@ func TestNodeSelectorsAreSetForEachComponent(t *testing.T) {
 					NodeSelector: nodeSelectors,
 					Replicas:     1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					NodeSelector: nodeSelectors,
+					Replicas:     1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					NodeSelector: nodeSelectors,
+					Replicas:     1,
+				},
+				Gateway: &lokiv1beta1.LokiComponentSpec{
+					NodeSelector: nodeSelectors,
+					Replicas:     1,
+				},
 			},
 		},
-		ObjectStorage: ObjectStorage{},
+		ObjectStorage: storage.Options{},
 	}
 
 	optsWithoutNodeSelectors := Options{
@@ -140,9 +190,18 @@ func TestNodeSelectorsAreSetForEachComponent(t *testing.T) {
 				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				Gateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
 			},
 		},
-		ObjectStorage: ObjectStorage{},
+		ObjectStorage: storage.Options{},
 	}
 
 	t.Run("distributor", func(t *testing.T) {
@@ -169,4 +228,210 @@ func TestNodeSelectorsAreSetForEachComponent(t *testing.T) {
 		assert.Equal(t, nodeSelectors, NewCompactorStatefulSet(optsWithNodeSelectors).Spec.Template.Spec.NodeSelector)
 		assert.Empty(t, NewCompactorStatefulSet(optsWithoutNodeSelectors).Spec.Template.Spec.NodeSelector)
 	})
+
+	t.Run("ruler", func(t *testing.T) {
+		assert.Equal(t, nodeSelectors, NewRulerStatefulSet(optsWithNodeSelectors).Spec.Template.Spec.NodeSelector)
+		assert.Empty(t, NewRulerStatefulSet(optsWithoutNodeSelectors).Spec.Template.Spec.NodeSelector)
+	})
+
+	t.Run("index gateway", func(t *testing.T) {
+		assert.Equal(t, nodeSelectors, NewIndexGatewayStatefulSet(optsWithNodeSelectors).Spec.Template.Spec.NodeSelector)
+		assert.Empty(t, NewIndexGatewayStatefulSet(optsWithoutNodeSelectors).Spec.Template.Spec.NodeSelector)
+	})
+
+	t.Run("gateway", func(t *testing.T) {
+		assert.Equal(t, nodeSelectors, NewGatewayDeployment(optsWithNodeSelectors, "").Spec.Template.Spec.NodeSelector)
+		assert.Empty(t, NewGatewayDeployment(optsWithoutNodeSelectors, "").Spec.Template.Spec.NodeSelector)
+	})
+}
+
+// componentPodTemplates returns the pod template of each component
+// built with the given template spec.
+func componentPodTemplates(spec lokiv1beta1.LokiComponentSpec) map[string]corev1.PodTemplateSpec {
+	spec.Replicas = 1
+	opts := Options{
+		Name: "abcd",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Compactor:     spec.DeepCopy(),
+				Distributor:   spec.DeepCopy(),
+				Ingester:      spec.DeepCopy(),
+				Querier:       spec.DeepCopy(),
+				QueryFrontend: spec.DeepCopy(),
+				Ruler:         spec.DeepCopy(),
+				IndexGateway:  spec.DeepCopy(),
+				Gateway:       spec.DeepCopy(),
+			},
+		},
+		ObjectStorage: storage.Options{},
+	}
+
+	return map[string]corev1.PodTemplateSpec{
+		"distributor":    NewDistributorDeployment(opts).Spec.Template,
+		"query_frontend": NewQueryFrontendDeployment(opts).Spec.Template,
+		"querier":        NewQuerierStatefulSet(opts).Spec.Template,
+		"ingester":       NewIngesterStatefulSet(opts).Spec.Template,
+		"compactor":      NewCompactorStatefulSet(opts).Spec.Template,
+		"ruler":          NewRulerStatefulSet(opts).Spec.Template,
+		"index gateway":  NewIndexGatewayStatefulSet(opts).Spec.Template,
+		"gateway":        NewGatewayDeployment(opts, "").Spec.Template,
+	}
+}
+
+func TestAffinityIsSetForEachComponent(t *testing.T) {
+	affinity := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{
+								Key:      "type",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{"storage"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	withAffinity := componentPodTemplates(lokiv1beta1.LokiComponentSpec{Affinity: affinity})
+	withoutAffinity := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withAffinity {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, affinity, tpl.Spec.Affinity)
+			assert.Nil(t, withoutAffinity[name].Spec.Affinity)
+		})
+	}
+}
+
+func TestPriorityClassNameIsSetForEachComponent(t *testing.T) {
+	withPriorityClass := componentPodTemplates(lokiv1beta1.LokiComponentSpec{PriorityClassName: "high-priority"})
+	withoutPriorityClass := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withPriorityClass {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, "high-priority", tpl.Spec.PriorityClassName)
+			assert.Empty(t, withoutPriorityClass[name].Spec.PriorityClassName)
+		})
+	}
+}
+
+func TestPodSecurityContextIsSetForEachComponent(t *testing.T) {
+	psc := &corev1.PodSecurityContext{
+		RunAsNonRoot: pointer.BoolPtr(true),
+		FSGroup:      pointer.Int64Ptr(10001),
+	}
+
+	withPodSecurityContext := componentPodTemplates(lokiv1beta1.LokiComponentSpec{PodSecurityContext: psc})
+	withoutPodSecurityContext := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withPodSecurityContext {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, psc, tpl.Spec.SecurityContext)
+			assert.Nil(t, withoutPodSecurityContext[name].Spec.SecurityContext)
+		})
+	}
+}
+
+func TestContainerSecurityContextIsSetForEachComponent(t *testing.T) {
+	sc := &corev1.SecurityContext{
+		AllowPrivilegeEscalation: pointer.BoolPtr(false),
+		ReadOnlyRootFilesystem:   pointer.BoolPtr(true),
+	}
+
+	withSecurityContext := componentPodTemplates(lokiv1beta1.LokiComponentSpec{SecurityContext: sc})
+	withoutSecurityContext := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withSecurityContext {
+		t.Run(name, func(t *testing.T) {
+			for _, c := range tpl.Spec.Containers {
+				assert.Equal(t, sc, c.SecurityContext)
+			}
+			for _, c := range withoutSecurityContext[name].Spec.Containers {
+				assert.Nil(t, c.SecurityContext)
+			}
+		})
+	}
+}
+
+func TestPodLabelsAndAnnotationsAreSetForEachComponent(t *testing.T) {
+	spec := lokiv1beta1.LokiComponentSpec{
+		PodLabels: map[string]string{
+			"team":                  "logging",
+			"loki.grafana.com/name": "overwritten",
+		},
+		PodAnnotations: map[string]string{
+			"example.com/owner": "logging",
+		},
+	}
+
+	withExtras := componentPodTemplates(spec)
+	withoutExtras := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withExtras {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, "logging", tpl.Labels["team"])
+			assert.Equal(t, "logging", tpl.Annotations["example.com/owner"])
+
+			// Operator labels take precedence
+			assert.Equal(t, "abcd", tpl.Labels[LabelStackName])
+
+			assert.NotContains(t, withoutExtras[name].Labels, "team")
+			assert.NotContains(t, withoutExtras[name].Annotations, "example.com/owner")
+		})
+	}
+}
+
+func TestImagePullSecretsAreSetForEachComponent(t *testing.T) {
+	secrets := []corev1.LocalObjectReference{{Name: "registry-credentials"}}
+
+	withSecrets := componentPodTemplates(lokiv1beta1.LokiComponentSpec{ImagePullSecrets: secrets})
+	withoutSecrets := componentPodTemplates(lokiv1beta1.LokiComponentSpec{})
+
+	for name, tpl := range withSecrets {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, secrets, tpl.Spec.ImagePullSecrets)
+			assert.Empty(t, withoutSecrets[name].Spec.ImagePullSecrets)
+		})
+	}
+}
+
+func TestAffinityIsMergedWithZoneAwareness(t *testing.T) {
+	opts := Options{
+		Name: "abcd",
+		Stack: lokiv1beta1.LokiStackSpec{
+			ZoneAwareness: &lokiv1beta1.ZoneAwarenessSpec{
+				TopologyKeys: []string{"topology.kubernetes.io/zone"},
+			},
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Querier: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+					Affinity: &corev1.Affinity{
+						PodAntiAffinity: &corev1.PodAntiAffinity{
+							PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+								{
+									Weight: 50,
+									PodAffinityTerm: corev1.PodAffinityTerm{
+										TopologyKey: "kubernetes.io/hostname",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	terms := NewQuerierStatefulSet(opts).Spec.Template.Spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+	assert.Len(t, terms, 2)
+	assert.Equal(t, "kubernetes.io/hostname", terms[0].PodAffinityTerm.TopologyKey)
+	assert.Equal(t, "topology.kubernetes.io/zone", terms[1].PodAffinityTerm.TopologyKey)
+
+	// The template spec is left untouched
+	assert.Len(t, opts.Stack.Template.Querier.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 1)
 }
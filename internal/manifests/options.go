@@ -4,6 +4,9 @@ import (
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/manifests/internal"
 	"github.com/ViaQ/loki-operator/internal/manifests/openshift"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // Options is a set of configuration values to use when building manifests such as resource sizes, etc.
@@ -13,6 +16,7 @@ type Options struct {
 	Namespace         string
 	Image             string
 	GatewayImage      string
+	MemcachedImage    string
 	GatewayBaseDomain string
 	ConfigSHA1        string
 
@@ -21,20 +25,18 @@ type Options struct {
 	Stack                lokiv1beta1.LokiStackSpec
 	ResourceRequirements internal.ComponentResources
 
-	ObjectStorage ObjectStorage
+	// DeployedIngesterPVCSize is the storage requested by the volume claims
+	// of the deployed ingester statefulset, zero if not deployed yet.
+	DeployedIngesterPVCSize resource.Quantity
+
+	ObjectStorage storage.Options
 
 	OpenShiftOptions openshift.Options
 	TenantSecrets    []*TenantSecrets
 	TenantConfigMap  map[string]openshift.TenantData
-}
 
-// ObjectStorage for storage config.
-type ObjectStorage struct {
-	Endpoint        string
-	Region          string
-	Buckets         string
-	AccessKeyID     string
-	AccessKeySecret string
+	AlertingRules  []lokiv1beta1.AlertingRule
+	RecordingRules []lokiv1beta1.RecordingRule
 }
 
 // FeatureFlags contains flags that activate various features
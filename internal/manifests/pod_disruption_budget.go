@@ -0,0 +1,121 @@
+package manifests
+
+import (
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BuildPodDisruptionBudgets builds a pod disruption budget for each deployed component
+func BuildPodDisruptionBudgets(opts Options) []client.Object {
+	objs := []client.Object{
+		NewDistributorPodDisruptionBudget(opts),
+		NewIngesterPodDisruptionBudget(opts),
+		NewQuerierPodDisruptionBudget(opts),
+		NewCompactorPodDisruptionBudget(opts),
+		NewQueryFrontendPodDisruptionBudget(opts),
+	}
+
+	if rulerEnabled(opts) {
+		objs = append(objs, NewRulerPodDisruptionBudget(opts))
+	}
+
+	if indexGatewayEnabled(opts) {
+		objs = append(objs, NewIndexGatewayPodDisruptionBudget(opts))
+	}
+
+	if opts.Flags.EnableGateway {
+		objs = append(objs, NewGatewayPodDisruptionBudget(opts))
+	}
+
+	for _, cache := range managedCaches(opts) {
+		objs = append(objs, NewMemcachedPodDisruptionBudget(opts, cache))
+	}
+
+	return objs
+}
+
+// NewDistributorPodDisruptionBudget creates a pod disruption budget for the distributor component
+func NewDistributorPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelDistributorComponent, opts.Name)
+	return newPodDisruptionBudget(DistributorName(opts.Name), l, 1)
+}
+
+// NewIngesterPodDisruptionBudget creates a pod disruption budget for the ingester component.
+// At most as many ingesters as tolerated by the replication factor may be unavailable,
+// so that a quorum of replicas is kept for each stream. For replication factors of 1 and 2
+// the quorum includes every replica, so no ingester may be evicted and node drains block.
+func NewIngesterPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	maxUnavailable := (opts.Stack.ReplicationFactor - 1) / 2
+	if maxUnavailable < 0 {
+		maxUnavailable = 0
+	}
+
+	l := ComponentLabels(LabelIngesterComponent, opts.Name)
+	return newPodDisruptionBudget(IngesterName(opts.Name), l, int(maxUnavailable))
+}
+
+// NewQuerierPodDisruptionBudget creates a pod disruption budget for the querier component
+func NewQuerierPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelQuerierComponent, opts.Name)
+	return newPodDisruptionBudget(QuerierName(opts.Name), l, 1)
+}
+
+// NewCompactorPodDisruptionBudget creates a pod disruption budget for the compactor component.
+// The compactor is a singleton, so it must stay evictable during node drains.
+func NewCompactorPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelCompactorComponent, opts.Name)
+	return newPodDisruptionBudget(CompactorName(opts.Name), l, 1)
+}
+
+// NewQueryFrontendPodDisruptionBudget creates a pod disruption budget for the query-frontend component
+func NewQueryFrontendPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelQueryFrontendComponent, opts.Name)
+	return newPodDisruptionBudget(QueryFrontendName(opts.Name), l, 1)
+}
+
+// NewRulerPodDisruptionBudget creates a pod disruption budget for the ruler component
+func NewRulerPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelRulerComponent, opts.Name)
+	return newPodDisruptionBudget(RulerName(opts.Name), l, 1)
+}
+
+// NewIndexGatewayPodDisruptionBudget creates a pod disruption budget for the index gateway component
+func NewIndexGatewayPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelIndexGatewayComponent, opts.Name)
+	return newPodDisruptionBudget(IndexGatewayName(opts.Name), l, 1)
+}
+
+// NewGatewayPodDisruptionBudget creates a pod disruption budget for the lokistack-gateway component
+func NewGatewayPodDisruptionBudget(opts Options) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(LabelGatewayComponent, opts.Name)
+	return newPodDisruptionBudget(GatewayName(opts.Name), l, 1)
+}
+
+// NewMemcachedPodDisruptionBudget creates a pod disruption budget for a managed memcached cache
+func NewMemcachedPodDisruptionBudget(opts Options, cache string) *policyv1.PodDisruptionBudget {
+	l := ComponentLabels(memcachedComponent(cache), opts.Name)
+	return newPodDisruptionBudget(MemcachedName(opts.Name, cache), l, 1)
+}
+
+func newPodDisruptionBudget(name string, l labels.Set, maxUnavailable int) *policyv1.PodDisruptionBudget {
+	mu := intstr.FromInt(maxUnavailable)
+	return &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "PodDisruptionBudget",
+			APIVersion: policyv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: l,
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: l,
+			},
+			MaxUnavailable: &mu,
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package manifests_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+	policyv1 "k8s.io/api/policy/v1"
+)
+
+func TestNewIngesterPodDisruptionBudget_KeepsQuorum(t *testing.T) {
+	type test struct {
+		replicationFactor int32
+		maxUnavailable    int
+	}
+
+	table := []test{
+		{replicationFactor: 1, maxUnavailable: 0},
+		{replicationFactor: 2, maxUnavailable: 0},
+		{replicationFactor: 3, maxUnavailable: 1},
+		{replicationFactor: 4, maxUnavailable: 1},
+		{replicationFactor: 5, maxUnavailable: 2},
+	}
+
+	for _, tst := range table {
+		tst := tst
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+
+			opts := manifests.Options{
+				Name: "abcd",
+				Stack: lokiv1beta1.LokiStackSpec{
+					ReplicationFactor: tst.replicationFactor,
+				},
+			}
+
+			pdb := manifests.NewIngesterPodDisruptionBudget(opts)
+			require.Equal(t, tst.maxUnavailable, pdb.Spec.MaxUnavailable.IntValue())
+		})
+	}
+}
+
+func TestNewCompactorPodDisruptionBudget_AllowsEviction(t *testing.T) {
+	pdb := manifests.NewCompactorPodDisruptionBudget(manifests.Options{Name: "abcd"})
+	require.Equal(t, 1, pdb.Spec.MaxUnavailable.IntValue())
+	require.Nil(t, pdb.Spec.MinAvailable)
+}
+
+func TestBuildPodDisruptionBudgets_SelectComponentPods(t *testing.T) {
+	opts := manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			Size:              lokiv1beta1.SizeOneXSmall,
+			ReplicationFactor: 1,
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Compactor:     &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+				Distributor:   &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+				Ingester:      &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+				Querier:       &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+				QueryFrontend: &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+				IndexGateway:  &lokiv1beta1.LokiComponentSpec{Replicas: 1},
+			},
+		},
+	}
+
+	podLabels := map[string]map[string]string{
+		manifests.CompactorName("abcd"):     manifests.NewCompactorStatefulSet(opts).Spec.Template.Labels,
+		manifests.DistributorName("abcd"):   manifests.NewDistributorDeployment(opts).Spec.Template.Labels,
+		manifests.IngesterName("abcd"):      manifests.NewIngesterStatefulSet(opts).Spec.Template.Labels,
+		manifests.QuerierName("abcd"):       manifests.NewQuerierStatefulSet(opts).Spec.Template.Labels,
+		manifests.QueryFrontendName("abcd"): manifests.NewQueryFrontendDeployment(opts).Spec.Template.Labels,
+		manifests.IndexGatewayName("abcd"):  manifests.NewIndexGatewayStatefulSet(opts).Spec.Template.Labels,
+	}
+
+	objs := manifests.BuildPodDisruptionBudgets(opts)
+	require.Len(t, objs, len(podLabels))
+
+	for _, obj := range objs {
+		pdb := obj.(*policyv1.PodDisruptionBudget)
+
+		l, ok := podLabels[pdb.Name]
+		require.True(t, ok, "unexpected pod disruption budget %s", pdb.Name)
+		for k, v := range pdb.Spec.Selector.MatchLabels {
+			require.Equal(t, v, l[k])
+		}
+	}
+}
@@ -5,7 +5,9 @@ import (
 	"path"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,16 +26,27 @@ func BuildQuerier(opts Options) ([]client.Object, error) {
 		}
 	}
 
-	return []client.Object{
+	if err := storage.ConfigureStatefulSet(statefulSet, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{
 		statefulSet,
 		NewQuerierGRPCService(opts),
 		NewQuerierHTTPService(opts),
-	}, nil
+	}
+
+	if autoscalingEnabled(opts.Stack.Template.Querier) {
+		objs = append(objs, NewQuerierHorizontalPodAutoscaler(opts))
+	}
+
+	return objs, nil
 }
 
 // NewQuerierStatefulSet creates a deployment object for a querier
 func NewQuerierStatefulSet(opts Options) *appsv1.StatefulSet {
 	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
 		Volumes: []corev1.Volume{
 			{
 				Name: configVolumeName,
@@ -123,15 +136,10 @@ func NewQuerierStatefulSet(opts Options) *appsv1.StatefulSet {
 		},
 	}
 
-	if opts.Stack.Template != nil && opts.Stack.Template.Querier != nil {
-		podSpec.Tolerations = opts.Stack.Template.Querier.Tolerations
-		podSpec.NodeSelector = opts.Stack.Template.Querier.NodeSelector
-	}
-
 	l := ComponentLabels(LabelQuerierComponent, opts.Name)
-	a := commonAnnotations(opts.ConfigSHA1)
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
 
-	return &appsv1.StatefulSet{
+	sts := &appsv1.StatefulSet{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "StatefulSet",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -143,7 +151,7 @@ func NewQuerierStatefulSet(opts Options) *appsv1.StatefulSet {
 		Spec: appsv1.StatefulSetSpec{
 			PodManagementPolicy:  appsv1.OrderedReadyPodManagement,
 			RevisionHistoryLimit: pointer.Int32Ptr(10),
-			Replicas:             pointer.Int32Ptr(opts.Stack.Template.Querier.Replicas),
+			Replicas:             componentReplicas(opts.Stack.Template.Querier),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels.Merge(l, GossipLabels()),
 			},
@@ -178,6 +186,13 @@ func NewQuerierStatefulSet(opts Options) *appsv1.StatefulSet {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&sts.Spec.Template, opts.Stack.Template.Querier)
+	}
+	configureZoneAwareness(&sts.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return sts
 }
 
 // NewQuerierGRPCService creates a k8s service for the querier GRPC endpoint
@@ -238,6 +253,12 @@ func NewQuerierHTTPService(opts Options) *corev1.Service {
 	}
 }
 
+// NewQuerierHorizontalPodAutoscaler creates a horizontal pod autoscaler for the querier statefulset
+func NewQuerierHorizontalPodAutoscaler(opts Options) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	l := ComponentLabels(LabelQuerierComponent, opts.Name)
+	return newHorizontalPodAutoscaler(QuerierName(opts.Name), "StatefulSet", l, opts.Stack.Template.Querier.Autoscaling)
+}
+
 func configureQuerierServiceMonitorPKI(statefulSet *appsv1.StatefulSet, stackName string) error {
 	serviceName := serviceNameQuerierHTTP(stackName)
 	return configureServiceMonitorPKI(&statefulSet.Spec.Template.Spec, serviceName)
@@ -5,12 +5,13 @@ import (
 	"path"
 
 	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -23,16 +24,27 @@ func BuildQueryFrontend(opts Options) ([]client.Object, error) {
 		}
 	}
 
-	return []client.Object{
+	if err := storage.ConfigureDeployment(deployment, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
+	objs := []client.Object{
 		deployment,
 		NewQueryFrontendGRPCService(opts),
 		NewQueryFrontendHTTPService(opts),
-	}, nil
+	}
+
+	if autoscalingEnabled(opts.Stack.Template.QueryFrontend) {
+		objs = append(objs, NewQueryFrontendHorizontalPodAutoscaler(opts))
+	}
+
+	return objs, nil
 }
 
 // NewQueryFrontendDeployment creates a deployment object for a query-frontend
 func NewQueryFrontendDeployment(opts Options) *appsv1.Deployment {
 	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
 		Volumes: []corev1.Volume{
 			{
 				Name: configVolumeName,
@@ -123,15 +135,10 @@ func NewQueryFrontendDeployment(opts Options) *appsv1.Deployment {
 		},
 	}
 
-	if opts.Stack.Template != nil && opts.Stack.Template.QueryFrontend != nil {
-		podSpec.Tolerations = opts.Stack.Template.QueryFrontend.Tolerations
-		podSpec.NodeSelector = opts.Stack.Template.QueryFrontend.NodeSelector
-	}
-
 	l := ComponentLabels(LabelQueryFrontendComponent, opts.Name)
-	a := commonAnnotations(opts.ConfigSHA1)
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
 
-	return &appsv1.Deployment{
+	dpl := &appsv1.Deployment{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "Deployment",
 			APIVersion: appsv1.SchemeGroupVersion.String(),
@@ -141,7 +148,7 @@ func NewQueryFrontendDeployment(opts Options) *appsv1.Deployment {
 			Labels: l,
 		},
 		Spec: appsv1.DeploymentSpec{
-			Replicas: pointer.Int32Ptr(opts.Stack.Template.QueryFrontend.Replicas),
+			Replicas: componentReplicas(opts.Stack.Template.QueryFrontend),
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels.Merge(l, GossipLabels()),
 			},
@@ -158,6 +165,13 @@ func NewQueryFrontendDeployment(opts Options) *appsv1.Deployment {
 			},
 		},
 	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&dpl.Spec.Template, opts.Stack.Template.QueryFrontend)
+	}
+	configureZoneAwareness(&dpl.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return dpl
 }
 
 // NewQueryFrontendGRPCService creates a k8s service for the query-frontend GRPC endpoint
@@ -218,6 +232,12 @@ func NewQueryFrontendHTTPService(opts Options) *corev1.Service {
 	}
 }
 
+// NewQueryFrontendHorizontalPodAutoscaler creates a horizontal pod autoscaler for the query frontend deployment
+func NewQueryFrontendHorizontalPodAutoscaler(opts Options) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	l := ComponentLabels(LabelQueryFrontendComponent, opts.Name)
+	return newHorizontalPodAutoscaler(QueryFrontendName(opts.Name), "Deployment", l, opts.Stack.Template.QueryFrontend.Autoscaling)
+}
+
 func configureQueryFrontendServiceMonitorPKI(deployment *appsv1.Deployment, stackName string) error {
 	serviceName := serviceNameQueryFrontendHTTP(stackName)
 	return configureServiceMonitorPKI(&deployment.Spec.Template.Spec, serviceName)
@@ -0,0 +1,428 @@
+package manifests
+
+import (
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/ViaQ/loki-operator/internal/manifests/internal/config"
+	"github.com/ViaQ/loki-operator/internal/manifests/internal/rules"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	rulesVolumeName       = "rules"
+	rulesStorageDirectory = "/tmp/rules"
+)
+
+// BuildRuler builds the k8s objects required to run Loki Ruler.
+func BuildRuler(opts Options) ([]client.Object, error) {
+	cm, err := RulesConfigMap(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	statefulSet := NewRulerStatefulSet(opts)
+	if opts.Flags.EnableTLSServiceMonitorConfig {
+		if err := configureRulerServiceMonitorPKI(statefulSet, opts.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := storage.ConfigureStatefulSet(statefulSet, opts.ObjectStorage); err != nil {
+		return nil, err
+	}
+
+	return []client.Object{
+		cm,
+		statefulSet,
+		NewRulerGRPCService(opts),
+		NewRulerHTTPService(opts),
+	}, nil
+}
+
+// BuildObsoleteRuler returns the k8s objects of the Loki Ruler to delete if the ruler
+// is disabled. The persistent volume claims of the ruler are kept.
+func BuildObsoleteRuler(opts Options) []client.Object {
+	if rulerEnabled(opts) {
+		return nil
+	}
+
+	objs := []client.Object{
+		&corev1.ConfigMap{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "ConfigMap",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: RulesConfigMapName(opts.Name),
+			},
+		},
+		&appsv1.StatefulSet{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "StatefulSet",
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: RulerName(opts.Name),
+			},
+		},
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: serviceNameRulerGRPC(opts.Name),
+			},
+		},
+		&corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: serviceNameRulerHTTP(opts.Name),
+			},
+		},
+		&policyv1.PodDisruptionBudget{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "PodDisruptionBudget",
+				APIVersion: policyv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: RulerName(opts.Name),
+			},
+		},
+	}
+
+	// The ServiceMonitor kind is only known with the prometheus operator installed.
+	if opts.Flags.EnableServiceMonitors {
+		objs = append(objs, &monitoringv1.ServiceMonitor{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       monitoringv1.ServiceMonitorsKind,
+				APIVersion: monitoringv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: serviceMonitorName(RulerName(opts.Name)),
+			},
+		})
+	}
+
+	return objs
+}
+
+// RulesConfigMap creates the configmap containing a rule file for
+// each AlertingRule and RecordingRule evaluated by the ruler.
+func RulesConfigMap(opts Options) (*corev1.ConfigMap, error) {
+	data := make(map[string]string, len(opts.AlertingRules)+len(opts.RecordingRules))
+
+	for _, r := range opts.AlertingRules {
+		c, err := rules.MarshalAlertingRule(r)
+		if err != nil {
+			return nil, err
+		}
+		data[alertingRuleFileName(r.Name)] = c
+	}
+
+	for _, r := range opts.RecordingRules {
+		c, err := rules.MarshalRecordingRule(r)
+		if err != nil {
+			return nil, err
+		}
+		data[recordingRuleFileName(r.Name)] = c
+	}
+
+	return &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ConfigMap",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RulesConfigMapName(opts.Name),
+			Labels: commonLabels(opts.Name),
+		},
+		Data: data,
+	}, nil
+}
+
+// NewRulerStatefulSet creates a statefulset object for a ruler.
+func NewRulerStatefulSet(opts Options) *appsv1.StatefulSet {
+	podSpec := corev1.PodSpec{
+		ServiceAccountName: lokiServiceAccountName(opts),
+		Volumes: []corev1.Volume{
+			{
+				Name: configVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						DefaultMode: &defaultConfigMapMode,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: lokiConfigMapName(opts.Name),
+						},
+					},
+				},
+			},
+			{
+				Name: rulesVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						DefaultMode: &defaultConfigMapMode,
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: RulesConfigMapName(opts.Name),
+						},
+						Items: rulesConfigMapItems(opts),
+					},
+				},
+			},
+		},
+		Containers: []corev1.Container{
+			{
+				Image: opts.Image,
+				Name:  "loki-ruler",
+				Resources: corev1.ResourceRequirements{
+					Limits:   opts.ResourceRequirements.Ruler.Limits,
+					Requests: opts.ResourceRequirements.Ruler.Requests,
+				},
+				Args: []string{
+					"-target=ruler",
+					fmt.Sprintf("-config.file=%s", path.Join(config.LokiConfigMountDir, config.LokiConfigFileName)),
+					fmt.Sprintf("-runtime-config.file=%s", path.Join(config.LokiConfigMountDir, config.LokiRuntimeConfigFileName)),
+				},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path:   "/ready",
+							Port:   intstr.FromInt(httpPort),
+							Scheme: corev1.URISchemeHTTP,
+						},
+					},
+					PeriodSeconds:       10,
+					InitialDelaySeconds: 15,
+					TimeoutSeconds:      1,
+					SuccessThreshold:    1,
+					FailureThreshold:    3,
+				},
+				LivenessProbe: &corev1.Probe{
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{
+							Path:   "/metrics",
+							Port:   intstr.FromInt(httpPort),
+							Scheme: corev1.URISchemeHTTP,
+						},
+					},
+					TimeoutSeconds:   2,
+					PeriodSeconds:    30,
+					FailureThreshold: 10,
+					SuccessThreshold: 1,
+				},
+				Ports: []corev1.ContainerPort{
+					{
+						Name:          lokiHTTPPortName,
+						ContainerPort: httpPort,
+						Protocol:      protocolTCP,
+					},
+					{
+						Name:          lokiGRPCPortName,
+						ContainerPort: grpcPort,
+						Protocol:      protocolTCP,
+					},
+					{
+						Name:          lokiGossipPortName,
+						ContainerPort: gossipPort,
+						Protocol:      protocolTCP,
+					},
+				},
+				VolumeMounts: []corev1.VolumeMount{
+					{
+						Name:      configVolumeName,
+						ReadOnly:  false,
+						MountPath: config.LokiConfigMountDir,
+					},
+					{
+						Name:      rulesVolumeName,
+						ReadOnly:  true,
+						MountPath: rulesStorageDirectory,
+					},
+					{
+						Name:      storageVolumeName,
+						ReadOnly:  false,
+						MountPath: dataDirectory,
+					},
+				},
+				TerminationMessagePath:   "/dev/termination-log",
+				TerminationMessagePolicy: "File",
+				ImagePullPolicy:          "IfNotPresent",
+			},
+		},
+	}
+
+	l := ComponentLabels(LabelRulerComponent, opts.Name)
+	a := commonAnnotations(opts.ConfigSHA1, opts.ObjectStorage.SecretSHA1)
+	sts := &appsv1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: appsv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   RulerName(opts.Name),
+			Labels: l,
+		},
+		Spec: appsv1.StatefulSetSpec{
+			PodManagementPolicy:  appsv1.OrderedReadyPodManagement,
+			RevisionHistoryLimit: pointer.Int32Ptr(10),
+			Replicas:             pointer.Int32Ptr(opts.Stack.Template.Ruler.Replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels.Merge(l, GossipLabels()),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf("loki-ruler-%s", opts.Name),
+					Labels:      labels.Merge(l, GossipLabels()),
+					Annotations: a,
+				},
+				Spec: podSpec,
+			},
+			VolumeClaimTemplates: []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: l,
+						Name:   storageVolumeName,
+					},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{
+							corev1.ReadWriteOnce,
+						},
+						Resources: corev1.ResourceRequirements{
+							Requests: map[corev1.ResourceName]resource.Quantity{
+								corev1.ResourceStorage: opts.ResourceRequirements.Ruler.PVCSize,
+							},
+						},
+						VolumeMode:       &volumeFileSystemMode,
+						StorageClassName: pointer.StringPtr(opts.Stack.StorageClassName),
+					},
+				},
+			},
+		},
+	}
+
+	if opts.Stack.Template != nil {
+		configurePodTemplate(&sts.Spec.Template, opts.Stack.Template.Ruler)
+	}
+	configureZoneAwareness(&sts.Spec.Template.Spec, l, opts.Stack.ZoneAwareness)
+
+	return sts
+}
+
+// NewRulerGRPCService creates a k8s service for the ruler GRPC endpoint
+func NewRulerGRPCService(opts Options) *corev1.Service {
+	l := ComponentLabels(LabelRulerComponent, opts.Name)
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   serviceNameRulerGRPC(opts.Name),
+			Labels: l,
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: "None",
+			Ports: []corev1.ServicePort{
+				{
+					Name:       lokiGRPCPortName,
+					Port:       grpcPort,
+					Protocol:   protocolTCP,
+					TargetPort: intstr.IntOrString{IntVal: grpcPort},
+				},
+			},
+			Selector: l,
+		},
+	}
+}
+
+// NewRulerHTTPService creates a k8s service for the ruler HTTP endpoint
+func NewRulerHTTPService(opts Options) *corev1.Service {
+	serviceName := serviceNameRulerHTTP(opts.Name)
+	l := ComponentLabels(LabelRulerComponent, opts.Name)
+	a := serviceAnnotations(serviceName, opts.Flags.EnableCertificateSigningService)
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Service",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        serviceName,
+			Labels:      l,
+			Annotations: a,
+		},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{
+				{
+					Name:       lokiHTTPPortName,
+					Port:       httpPort,
+					Protocol:   protocolTCP,
+					TargetPort: intstr.IntOrString{IntVal: httpPort},
+				},
+			},
+			Selector: l,
+		},
+	}
+}
+
+func configureRulerServiceMonitorPKI(statefulSet *appsv1.StatefulSet, stackName string) error {
+	serviceName := serviceNameRulerHTTP(stackName)
+	return configureServiceMonitorPKI(&statefulSet.Spec.Template.Spec, serviceName)
+}
+
+// rulesConfigMapItems maps each rule file to the directory of its tenant,
+// as the ruler local storage expects the layout <directory>/<tenant>/<file>.
+func rulesConfigMapItems(opts Options) []corev1.KeyToPath {
+	var items []corev1.KeyToPath
+
+	for _, r := range opts.AlertingRules {
+		key := alertingRuleFileName(r.Name)
+		items = append(items, corev1.KeyToPath{
+			Key:  key,
+			Path: path.Join(r.Spec.TenantID, key),
+		})
+	}
+
+	for _, r := range opts.RecordingRules {
+		key := recordingRuleFileName(r.Name)
+		items = append(items, corev1.KeyToPath{
+			Key:  key,
+			Path: path.Join(r.Spec.TenantID, key),
+		})
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Key < items[j].Key
+	})
+
+	return items
+}
+
+func rulerEnabled(opts Options) bool {
+	return opts.Stack.Rules != nil && opts.Stack.Rules.Enabled
+}
+
+func alertingRuleFileName(name string) string {
+	return fmt.Sprintf("alerting-%s.yaml", name)
+}
+
+func recordingRuleFileName(name string) string {
+	return fmt.Sprintf("recording-%s.yaml", name)
+}
@@ -0,0 +1,211 @@
+package manifests_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewRulerStatefulSet_SelectorMatchesLabels(t *testing.T) {
+	sts := manifests.NewRulerStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+
+	l := sts.Spec.Template.GetObjectMeta().GetLabels()
+	for key, value := range sts.Spec.Selector.MatchLabels {
+		require.Contains(t, l, key)
+		require.Equal(t, l[key], value)
+	}
+}
+
+func TestNewRulerStatefulSet_HasTemplateConfigHashAnnotation(t *testing.T) {
+	ss := manifests.NewRulerStatefulSet(manifests.Options{
+		Name:       "abcd",
+		Namespace:  "efgh",
+		ConfigSHA1: "deadbeef",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+	})
+	expected := "loki.openshift.io/config-hash"
+	annotations := ss.Spec.Template.Annotations
+	require.Contains(t, annotations, expected)
+	require.Equal(t, annotations[expected], "deadbeef")
+}
+
+func TestNewRulerStatefulSet_MountsRuleFilesPerTenant(t *testing.T) {
+	ss := manifests.NewRulerStatefulSet(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			StorageClassName: "standard",
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+			},
+		},
+		AlertingRules: []lokiv1beta1.AlertingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "alerts"},
+				Spec:       lokiv1beta1.AlertingRuleSpec{TenantID: "application"},
+			},
+		},
+		RecordingRules: []lokiv1beta1.RecordingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "recordings"},
+				Spec:       lokiv1beta1.RecordingRuleSpec{TenantID: "infrastructure"},
+			},
+		},
+	})
+
+	expected := []corev1.KeyToPath{
+		{
+			Key:  "alerting-alerts.yaml",
+			Path: "application/alerting-alerts.yaml",
+		},
+		{
+			Key:  "recording-recordings.yaml",
+			Path: "infrastructure/recording-recordings.yaml",
+		},
+	}
+
+	for _, v := range ss.Spec.Template.Spec.Volumes {
+		if v.Name != "rules" {
+			continue
+		}
+
+		require.Equal(t, manifests.RulesConfigMapName("abcd"), v.ConfigMap.Name)
+		require.Equal(t, expected, v.ConfigMap.Items)
+		return
+	}
+
+	t.Fatal("rules volume not found")
+}
+
+func TestRulesConfigMap_HasRuleFiles(t *testing.T) {
+	cm, err := manifests.RulesConfigMap(manifests.Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		AlertingRules: []lokiv1beta1.AlertingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "alerts"},
+				Spec: lokiv1beta1.AlertingRuleSpec{
+					TenantID: "application",
+					Groups: []*lokiv1beta1.AlertingRuleGroup{
+						{
+							Name: "group",
+							Rules: []*lokiv1beta1.AlertingRuleGroupSpec{
+								{
+									Alert: "HighErrorRate",
+									Expr:  `sum(rate({app="foo"} |= "error" [5m])) > 10`,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		RecordingRules: []lokiv1beta1.RecordingRule{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "recordings"},
+				Spec: lokiv1beta1.RecordingRuleSpec{
+					TenantID: "application",
+					Groups: []*lokiv1beta1.RecordingRuleGroup{
+						{
+							Name: "group",
+							Rules: []*lokiv1beta1.RecordingRuleGroupSpec{
+								{
+									Record: "foo:requests:rate5m",
+									Expr:   `sum(rate({app="foo"}[5m]))`,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, manifests.RulesConfigMapName("abcd"), cm.Name)
+	require.Len(t, cm.Data, 2)
+	require.Contains(t, cm.Data["alerting-alerts.yaml"], "alert: HighErrorRate")
+	require.Contains(t, cm.Data["recording-recordings.yaml"], "record: foo:requests:rate5m")
+}
+
+func TestBuildObsoleteRuler(t *testing.T) {
+	type test struct {
+		name  string
+		rules *lokiv1beta1.RulesSpec
+		flags manifests.FeatureFlags
+		want  []string
+	}
+	table := []test{
+		{
+			name:  "ruler enabled",
+			rules: &lokiv1beta1.RulesSpec{Enabled: true},
+		},
+		{
+			name: "ruler not defined",
+			want: []string{
+				"ConfigMap/loki-rules-abcd",
+				"StatefulSet/loki-ruler-abcd",
+				"Service/loki-ruler-grpc-abcd",
+				"Service/loki-ruler-http-abcd",
+				"PodDisruptionBudget/loki-ruler-abcd",
+			},
+		},
+		{
+			name:  "ruler disabled with service monitors",
+			rules: &lokiv1beta1.RulesSpec{},
+			flags: manifests.FeatureFlags{EnableServiceMonitors: true},
+			want: []string{
+				"ConfigMap/loki-rules-abcd",
+				"StatefulSet/loki-ruler-abcd",
+				"Service/loki-ruler-grpc-abcd",
+				"Service/loki-ruler-http-abcd",
+				"PodDisruptionBudget/loki-ruler-abcd",
+				"ServiceMonitor/monitor-loki-ruler-abcd",
+			},
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			objs := manifests.BuildObsoleteRuler(manifests.Options{
+				Name:      "abcd",
+				Namespace: "efgh",
+				Stack: lokiv1beta1.LokiStackSpec{
+					Rules: tst.rules,
+				},
+				Flags: tst.flags,
+			})
+
+			var got []string
+			for _, obj := range objs {
+				got = append(got, obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName())
+			}
+			require.Equal(t, tst.want, got)
+		})
+	}
+}
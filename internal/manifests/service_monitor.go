@@ -13,7 +13,7 @@ import (
 
 // BuildServiceMonitors builds the service monitors
 func BuildServiceMonitors(opts Options) []client.Object {
-	return []client.Object{
+	objs := []client.Object{
 		NewDistributorServiceMonitor(opts),
 		NewIngesterServiceMonitor(opts),
 		NewQuerierServiceMonitor(opts),
@@ -21,6 +21,16 @@ func BuildServiceMonitors(opts Options) []client.Object {
 		NewQueryFrontendServiceMonitor(opts),
 		NewGatewayServiceMonitor(opts),
 	}
+
+	if rulerEnabled(opts) {
+		objs = append(objs, NewRulerServiceMonitor(opts))
+	}
+
+	if indexGatewayEnabled(opts) {
+		objs = append(objs, NewIndexGatewayServiceMonitor(opts))
+	}
+
+	return objs
 }
 
 // NewDistributorServiceMonitor creates a k8s service monitor for the distributor component
@@ -67,6 +77,28 @@ func NewCompactorServiceMonitor(opts Options) *monitoringv1.ServiceMonitor {
 	return newServiceMonitor(opts.Namespace, serviceMonitorName, l, lokiEndpoint)
 }
 
+// NewRulerServiceMonitor creates a k8s service monitor for the ruler component
+func NewRulerServiceMonitor(opts Options) *monitoringv1.ServiceMonitor {
+	l := ComponentLabels(LabelRulerComponent, opts.Name)
+
+	serviceMonitorName := serviceMonitorName(RulerName(opts.Name))
+	serviceName := serviceNameRulerHTTP(opts.Name)
+	lokiEndpoint := serviceMonitorEndpoint(lokiHTTPPortName, serviceName, opts.Namespace, opts.Flags.EnableTLSServiceMonitorConfig)
+
+	return newServiceMonitor(opts.Namespace, serviceMonitorName, l, lokiEndpoint)
+}
+
+// NewIndexGatewayServiceMonitor creates a k8s service monitor for the index gateway component
+func NewIndexGatewayServiceMonitor(opts Options) *monitoringv1.ServiceMonitor {
+	l := ComponentLabels(LabelIndexGatewayComponent, opts.Name)
+
+	serviceMonitorName := serviceMonitorName(IndexGatewayName(opts.Name))
+	serviceName := serviceNameIndexGatewayHTTP(opts.Name)
+	lokiEndpoint := serviceMonitorEndpoint(lokiHTTPPortName, serviceName, opts.Namespace, opts.Flags.EnableTLSServiceMonitorConfig)
+
+	return newServiceMonitor(opts.Namespace, serviceMonitorName, l, lokiEndpoint)
+}
+
 // NewQueryFrontendServiceMonitor creates a k8s service monitor for the query-frontend component
 func NewQueryFrontendServiceMonitor(opts Options) *monitoringv1.ServiceMonitor {
 	l := ComponentLabels(LabelQueryFrontendComponent, opts.Name)
@@ -49,6 +49,12 @@ func TestServiceMonitorMatchLabels(t *testing.T) {
 				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
 				Gateway: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
@@ -77,6 +83,14 @@ func TestServiceMonitorMatchLabels(t *testing.T) {
 			Service:        NewCompactorHTTPService(opt),
 			ServiceMonitor: NewCompactorServiceMonitor(opt),
 		},
+		{
+			Service:        NewRulerHTTPService(opt),
+			ServiceMonitor: NewRulerServiceMonitor(opt),
+		},
+		{
+			Service:        NewIndexGatewayHTTPService(opt),
+			ServiceMonitor: NewIndexGatewayServiceMonitor(opt),
+		},
 		{
 			Service:        NewGatewayHTTPService(opt),
 			ServiceMonitor: NewGatewayServiceMonitor(opt),
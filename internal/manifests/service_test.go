@@ -38,6 +38,12 @@ func TestServicesMatchPorts(t *testing.T) {
 				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
 				Gateway: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
@@ -82,6 +88,20 @@ func TestServicesMatchPorts(t *testing.T) {
 				NewCompactorHTTPService(opt),
 			},
 		},
+		{
+			Containers: NewRulerStatefulSet(opt).Spec.Template.Spec.Containers,
+			Services: []*corev1.Service{
+				NewRulerGRPCService(opt),
+				NewRulerHTTPService(opt),
+			},
+		},
+		{
+			Containers: NewIndexGatewayStatefulSet(opt).Spec.Template.Spec.Containers,
+			Services: []*corev1.Service{
+				NewIndexGatewayGRPCService(opt),
+				NewIndexGatewayHTTPService(opt),
+			},
+		},
 		{
 			Containers: NewGatewayDeployment(opt, sha1C).Spec.Template.Spec.Containers,
 			Services: []*corev1.Service{
@@ -147,6 +167,12 @@ func TestServicesMatchLabels(t *testing.T) {
 				QueryFrontend: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
+				Ruler: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
+				IndexGateway: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 1,
+				},
 				Gateway: &lokiv1beta1.LokiComponentSpec{
 					Replicas: 1,
 				},
@@ -191,6 +217,20 @@ func TestServicesMatchLabels(t *testing.T) {
 				NewCompactorHTTPService(opt),
 			},
 		},
+		{
+			Object: NewRulerStatefulSet(opt),
+			Services: []*corev1.Service{
+				NewRulerGRPCService(opt),
+				NewRulerHTTPService(opt),
+			},
+		},
+		{
+			Object: NewIndexGatewayStatefulSet(opt),
+			Services: []*corev1.Service{
+				NewIndexGatewayGRPCService(opt),
+				NewIndexGatewayHTTPService(opt),
+			},
+		},
 		{
 			Object: NewGatewayDeployment(opt, sha1C),
 			Services: []*corev1.Service{
@@ -0,0 +1,38 @@
+package manifests
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// BuildServiceAccount returns a k8s object for the ServiceAccount shared by the
+// Loki components. Its projected token is exchanged for short-lived object storage
+// credentials, thus the IAM role trust policy must allow this ServiceAccount.
+func BuildServiceAccount(opts Options) client.Object {
+	return &corev1.ServiceAccount{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ServiceAccount",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   ServiceAccountName(opts.Name),
+			Labels: commonLabels(opts.Name),
+		},
+	}
+}
+
+// requiresServiceAccount returns true if the Loki components need a dedicated
+// ServiceAccount, i.e. to assume an IAM role with a projected token.
+func requiresServiceAccount(opts Options) bool {
+	return opts.ObjectStorage.S3 != nil && opts.ObjectStorage.S3.STS != nil
+}
+
+// lokiServiceAccountName returns the ServiceAccount name for the Loki component pods.
+// An empty name selects the default ServiceAccount of the namespace.
+func lokiServiceAccountName(opts Options) string {
+	if !requiresServiceAccount(opts) {
+		return ""
+	}
+	return ServiceAccountName(opts.Name)
+}
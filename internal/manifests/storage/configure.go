@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"path"
+	"sort"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/imdario/mergo"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ConfigureDeployment appends additional pod volumes and container env vars, args, volume mounts
+// based on the object storage type. Currently supported amendments:
+// - Azure, S3, Swift: Ensure credential env vars from the object storage secret in container
+// - S3 with STS: Mount the projected service account token and ensure env vars to assume the IAM role
+// - GCS: Ensure env var GOOGLE_APPLICATION_CREDENTIALS in container
+// - TLS: Mount the CA ConfigMap used to verify the object storage endpoint
+func ConfigureDeployment(d *appsv1.Deployment, opts Options) error {
+	return configurePodSpec(&d.Spec.Template.Spec, opts)
+}
+
+// ConfigureStatefulSet appends additional pod volumes and container env vars, args, volume mounts
+// based on the object storage type. Currently supported amendments:
+// - Azure, S3, Swift: Ensure credential env vars from the object storage secret in container
+// - S3 with STS: Mount the projected service account token and ensure env vars to assume the IAM role
+// - GCS: Ensure env var GOOGLE_APPLICATION_CREDENTIALS in container
+// - TLS: Mount the CA ConfigMap used to verify the object storage endpoint
+func ConfigureStatefulSet(s *appsv1.StatefulSet, opts Options) error {
+	return configurePodSpec(&s.Spec.Template.Spec, opts)
+}
+
+func configurePodSpec(p *corev1.PodSpec, opts Options) error {
+	var err error
+	switch opts.SharedStore {
+	case lokiv1beta1.ObjectStorageSecretAzure:
+		err = ensureCredentialsFromEnv(p, opts.SecretName, map[string]string{
+			EnvAzureStorageAccountKey: keyAzureAccountKey,
+		})
+	case lokiv1beta1.ObjectStorageSecretGCS:
+		err = ensureCredentialsForGCS(p, opts.SecretName)
+	case lokiv1beta1.ObjectStorageSecretS3:
+		if opts.S3 != nil && opts.S3.STS != nil {
+			err = ensureCredentialsForSTS(p, opts.S3.STS)
+			break
+		}
+		err = ensureCredentialsFromEnv(p, opts.SecretName, map[string]string{
+			EnvAWSAccessKeyID:     keyAWSAccessKeyID,
+			EnvAWSAccessKeySecret: keyAWSAccessKeySecret,
+		})
+	case lokiv1beta1.ObjectStorageSecretSwift:
+		err = ensureCredentialsFromEnv(p, opts.SecretName, map[string]string{
+			EnvSwiftPassword: keySwiftPassword,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if opts.TLS != nil && opts.TLS.CA != "" {
+		return ensureCAForObjectStorage(p, opts.TLS.CA)
+	}
+
+	return nil
+}
+
+// ensureCredentialsFromEnv exposes the given secret keys as container env vars, which
+// are referenced in the Loki configuration file by expanding environment variables.
+func ensureCredentialsFromEnv(p *corev1.PodSpec, secretName string, envToKey map[string]string) error {
+	names := make([]string, 0, len(envToKey))
+	for name := range envToKey {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]corev1.EnvVar, 0, len(names))
+	for _, name := range names {
+		env = append(env, corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secretName,
+					},
+					Key: envToKey[name],
+				},
+			},
+		})
+	}
+
+	secretContainerSpec := corev1.Container{
+		Args: []string{expandEnvArg},
+		Env:  env,
+	}
+
+	if err := mergo.Merge(&p.Containers[0], secretContainerSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge object storage credentials container")
+	}
+
+	return nil
+}
+
+// ensureCredentialsForSTS mounts a projected service account token and exposes the
+// IAM role to assume, so that the AWS SDK exchanges the token for short-lived credentials.
+func ensureCredentialsForSTS(p *corev1.PodSpec, sts *STSConfig) error {
+	expirationSeconds := saTokenExpirationSeconds
+	tokenVolumeSpec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{
+				Name: saTokenVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					Projected: &corev1.ProjectedVolumeSource{
+						Sources: []corev1.VolumeProjection{
+							{
+								ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+									Audience:          sts.Audience,
+									ExpirationSeconds: &expirationSeconds,
+									Path:              saTokenFileName,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	tokenContainerSpec := corev1.Container{
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      saTokenVolumeName,
+				ReadOnly:  true,
+				MountPath: saTokenDirectory,
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  EnvAWSRoleARN,
+				Value: sts.RoleARN,
+			},
+			{
+				Name:  EnvAWSWebIdentityTokenFile,
+				Value: path.Join(saTokenDirectory, saTokenFileName),
+			},
+		},
+	}
+
+	if err := mergo.Merge(p, tokenVolumeSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge sts object storage volumes")
+	}
+
+	if err := mergo.Merge(&p.Containers[0], tokenContainerSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge sts object storage container")
+	}
+
+	return nil
+}
+
+func ensureCredentialsForGCS(p *corev1.PodSpec, secretName string) error {
+	secretVolumeSpec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{
+				Name: secretName,
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{
+						SecretName: secretName,
+					},
+				},
+			},
+		},
+	}
+	secretContainerSpec := corev1.Container{
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      secretName,
+				ReadOnly:  false,
+				MountPath: secretDirectory,
+			},
+		},
+		Env: []corev1.EnvVar{
+			{
+				Name:  EnvGoogleApplicationCredentials,
+				Value: path.Join(secretDirectory, GCSFileName),
+			},
+		},
+	}
+
+	if err := mergo.Merge(p, secretVolumeSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge gcs object storage volumes")
+	}
+
+	if err := mergo.Merge(&p.Containers[0], secretContainerSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge gcs object storage container")
+	}
+
+	return nil
+}
+
+func ensureCAForObjectStorage(p *corev1.PodSpec, caName string) error {
+	caVolumeSpec := corev1.PodSpec{
+		Volumes: []corev1.Volume{
+			{
+				Name: caVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: caName,
+						},
+					},
+				},
+			},
+		},
+	}
+	caContainerSpec := corev1.Container{
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      caVolumeName,
+				ReadOnly:  true,
+				MountPath: caDirectory,
+			},
+		},
+	}
+
+	if err := mergo.Merge(p, caVolumeSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge object storage ca volumes")
+	}
+
+	if err := mergo.Merge(&p.Containers[0], caContainerSpec, mergo.WithAppendSlice); err != nil {
+		return kverrors.Wrap(err, "failed to merge object storage ca container")
+	}
+
+	return nil
+}
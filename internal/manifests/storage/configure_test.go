@@ -0,0 +1,444 @@
+package storage_test
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/manifests/storage"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigureDeploymentForStorageType(t *testing.T) {
+	type tt struct {
+		desc string
+		opts storage.Options
+		dpl  *appsv1.Deployment
+		want *appsv1.Deployment
+	}
+
+	tc := []tt{
+		{
+			desc: "object storage S3",
+			opts: storage.Options{
+				SecretName:  "test",
+				SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+			},
+			dpl: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+									Args: []string{
+										"-config.expand-env=true",
+									},
+									Env: []corev1.EnvVar{
+										{
+											Name: storage.EnvAWSAccessKeyID,
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: "test",
+													},
+													Key: "access_key_id",
+												},
+											},
+										},
+										{
+											Name: storage.EnvAWSAccessKeySecret,
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: "test",
+													},
+													Key: "access_key_secret",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "object storage GCS",
+			opts: storage.Options{
+				SecretName:  "test",
+				SharedStore: lokiv1beta1.ObjectStorageSecretGCS,
+			},
+			dpl: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &appsv1.Deployment{
+				Spec: appsv1.DeploymentSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "test",
+											ReadOnly:  false,
+											MountPath: "/etc/storage/secrets",
+										},
+									},
+									Env: []corev1.EnvVar{
+										{
+											Name:  storage.EnvGoogleApplicationCredentials,
+											Value: "/etc/storage/secrets/key.json",
+										},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "test",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "test",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tc {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			err := storage.ConfigureDeployment(tc.dpl, tc.opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, tc.dpl)
+		})
+	}
+}
+
+func TestConfigureStatefulSetForStorageType(t *testing.T) {
+	type tt struct {
+		desc string
+		opts storage.Options
+		sts  *appsv1.StatefulSet
+		want *appsv1.StatefulSet
+	}
+
+	tc := []tt{
+		{
+			desc: "object storage Azure",
+			opts: storage.Options{
+				SecretName:  "test",
+				SharedStore: lokiv1beta1.ObjectStorageSecretAzure,
+			},
+			sts: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+									Args: []string{
+										"-config.expand-env=true",
+									},
+									Env: []corev1.EnvVar{
+										{
+											Name: storage.EnvAzureStorageAccountKey,
+											ValueFrom: &corev1.EnvVarSource{
+												SecretKeyRef: &corev1.SecretKeySelector{
+													LocalObjectReference: corev1.LocalObjectReference{
+														Name: "test",
+													},
+													Key: "account_key",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "object storage GCS",
+			opts: storage.Options{
+				SecretName:  "test",
+				SharedStore: lokiv1beta1.ObjectStorageSecretGCS,
+			},
+			sts: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &appsv1.StatefulSet{
+				Spec: appsv1.StatefulSetSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name: "loki-ingester",
+									VolumeMounts: []corev1.VolumeMount{
+										{
+											Name:      "test",
+											ReadOnly:  false,
+											MountPath: "/etc/storage/secrets",
+										},
+									},
+									Env: []corev1.EnvVar{
+										{
+											Name:  storage.EnvGoogleApplicationCredentials,
+											Value: "/etc/storage/secrets/key.json",
+										},
+									},
+								},
+							},
+							Volumes: []corev1.Volume{
+								{
+									Name: "test",
+									VolumeSource: corev1.VolumeSource{
+										Secret: &corev1.SecretVolumeSource{
+											SecretName: "test",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tc {
+		tc := tc
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			err := storage.ConfigureStatefulSet(tc.sts, tc.opts)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, tc.sts)
+		})
+	}
+}
+
+func TestConfigureStatefulSetForStorageCA(t *testing.T) {
+	opts := storage.Options{
+		SecretName:  "test",
+		SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+		TLS: &storage.TLSConfig{
+			CA:    "storage-ca",
+			CAKey: storage.DefaultCAKey,
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "loki-ingester",
+						},
+					},
+				},
+			},
+		},
+	}
+	want := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "loki-ingester",
+							Args: []string{
+								"-config.expand-env=true",
+							},
+							Env: []corev1.EnvVar{
+								{
+									Name: storage.EnvAWSAccessKeyID,
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "test",
+											},
+											Key: "access_key_id",
+										},
+									},
+								},
+								{
+									Name: storage.EnvAWSAccessKeySecret,
+									ValueFrom: &corev1.EnvVarSource{
+										SecretKeyRef: &corev1.SecretKeySelector{
+											LocalObjectReference: corev1.LocalObjectReference{
+												Name: "test",
+											},
+											Key: "access_key_secret",
+										},
+									},
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "storage-tls",
+									ReadOnly:  true,
+									MountPath: "/var/run/tls/storage",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "storage-tls",
+							VolumeSource: corev1.VolumeSource{
+								ConfigMap: &corev1.ConfigMapVolumeSource{
+									LocalObjectReference: corev1.LocalObjectReference{
+										Name: "storage-ca",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := storage.ConfigureStatefulSet(sts, opts)
+	require.NoError(t, err)
+	require.Equal(t, want, sts)
+	require.Equal(t, "/var/run/tls/storage/service-ca.crt", opts.TLS.CAFile())
+}
+
+func TestConfigureStatefulSetForStorageSTS(t *testing.T) {
+	opts := storage.Options{
+		SecretName:  "test",
+		SharedStore: lokiv1beta1.ObjectStorageSecretS3,
+		S3: &storage.S3StorageConfig{
+			STS: &storage.STSConfig{
+				RoleARN:  "arn:aws:iam::123456789012:role/loki",
+				Audience: storage.DefaultSTSAudience,
+			},
+		},
+	}
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "loki-ingester",
+						},
+					},
+				},
+			},
+		},
+	}
+	expirationSeconds := int64(3600)
+	want := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "loki-ingester",
+							Env: []corev1.EnvVar{
+								{
+									Name:  storage.EnvAWSRoleARN,
+									Value: "arn:aws:iam::123456789012:role/loki",
+								},
+								{
+									Name:  storage.EnvAWSWebIdentityTokenFile,
+									Value: "/var/run/secrets/storage/serviceaccount/token",
+								},
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "bound-sa-token",
+									ReadOnly:  true,
+									MountPath: "/var/run/secrets/storage/serviceaccount",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "bound-sa-token",
+							VolumeSource: corev1.VolumeSource{
+								Projected: &corev1.ProjectedVolumeSource{
+									Sources: []corev1.VolumeProjection{
+										{
+											ServiceAccountToken: &corev1.ServiceAccountTokenProjection{
+												Audience:          "sts.amazonaws.com",
+												ExpirationSeconds: &expirationSeconds,
+												Path:              "token",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	err := storage.ConfigureStatefulSet(sts, opts)
+	require.NoError(t, err)
+	require.Equal(t, want, sts)
+}
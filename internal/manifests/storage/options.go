@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"path"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+)
+
+// Options is used to configure Loki to integrate with
+// supported object storages.
+type Options struct {
+	SecretName  string
+	SecretSHA1  string
+	SharedStore lokiv1beta1.ObjectStorageSecretType
+	Schemas     []lokiv1beta1.ObjectStorageSchema
+
+	Azure *AzureStorageConfig
+	GCS   *GCSStorageConfig
+	S3    *S3StorageConfig
+	Swift *SwiftStorageConfig
+
+	TLS *TLSConfig
+}
+
+// AzureStorageConfig for Azure storage config
+type AzureStorageConfig struct {
+	Env            string
+	Container      string
+	AccountName    string
+	EndpointSuffix string
+	// AccountKey is read from the object storage secret.
+	// It is only used by the operator to probe the container.
+	AccountKey string
+}
+
+// GCSStorageConfig for GCS storage config
+type GCSStorageConfig struct {
+	Bucket string
+	// ServiceAccountKey is the content of key.json read from the object storage secret.
+	// It is only used by the operator to probe the bucket.
+	ServiceAccountKey string
+}
+
+// S3StorageConfig for S3 storage config
+type S3StorageConfig struct {
+	Endpoint string
+	Region   string
+	Buckets  string
+	STS      *STSConfig
+	SSE      *SSEConfig
+	// AccessKeyID and AccessKeySecret are read from the object storage secret, unless STS is used.
+	// They are only used by the operator to probe the buckets.
+	AccessKeyID     string
+	AccessKeySecret string
+}
+
+// SSEConfig for S3 server-side encryption. The KMS encryption
+// context is a JSON encoded object of string values.
+type SSEConfig struct {
+	Type                 string
+	KMSKeyID             string
+	KMSEncryptionContext string
+}
+
+// STSConfig for short-lived AWS credentials by assuming an IAM role
+// with a projected service account token.
+type STSConfig struct {
+	RoleARN  string
+	Audience string
+}
+
+// SwiftStorageConfig for Swift storage config
+type SwiftStorageConfig struct {
+	AuthURL           string
+	Username          string
+	UserDomainName    string
+	ProjectName       string
+	ProjectDomainName string
+	Region            string
+	Container         string
+	// Password is read from the object storage secret.
+	// It is only used by the operator to probe the container.
+	Password string
+}
+
+// TLSConfig for the object storage endpoint. Currently only
+// supported for S3 and S3-compatible endpoints.
+type TLSConfig struct {
+	CA                 string
+	CAKey              string
+	InsecureSkipVerify bool
+	// CABundle is the PEM encoded CA certificate read from the CA ConfigMap.
+	// It is only used by the operator to probe the endpoint.
+	CABundle string
+}
+
+// CAFile returns the path of the CA certificate mounted into the component pods.
+func (t TLSConfig) CAFile() string {
+	return path.Join(caDirectory, t.CAKey)
+}
+
+// DefaultSchema returns the schema period applied if the spec does not define any.
+// It matches the CRD default and the schema used before periods were configurable.
+func DefaultSchema(objectStore lokiv1beta1.ObjectStorageSecretType) lokiv1beta1.ObjectStorageSchema {
+	return lokiv1beta1.ObjectStorageSchema{
+		Version:       lokiv1beta1.ObjectStorageSchemaV11,
+		EffectiveDate: "2020-10-01",
+		IndexStore:    lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper,
+		ObjectStore:   objectStore,
+	}
+}
@@ -0,0 +1,40 @@
+package storage
+
+const (
+	// EnvAWSAccessKeyID is the environment variable to specify the AWS access key id
+	EnvAWSAccessKeyID = "AWS_ACCESS_KEY_ID"
+	// EnvAWSAccessKeySecret is the environment variable to specify the AWS access key secret
+	EnvAWSAccessKeySecret = "AWS_ACCESS_KEY_SECRET"
+	// EnvAWSRoleARN is the environment variable to specify the AWS IAM role assumed via STS
+	EnvAWSRoleARN = "AWS_ROLE_ARN"
+	// EnvAWSWebIdentityTokenFile is the environment variable to specify the path to the projected service account token
+	EnvAWSWebIdentityTokenFile = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	// EnvAzureStorageAccountKey is the environment variable to specify the Azure storage account key
+	EnvAzureStorageAccountKey = "AZURE_STORAGE_ACCOUNT_KEY"
+	// EnvSwiftPassword is the environment variable to specify the OpenStack Swift password
+	EnvSwiftPassword = "SWIFT_PASSWORD"
+	// EnvGoogleApplicationCredentials is the environment variable to specify path to key.json
+	EnvGoogleApplicationCredentials = "GOOGLE_APPLICATION_CREDENTIALS"
+	// GCSFileName is the file containing the Google credentials for authentication
+	GCSFileName = "key.json"
+	// DefaultCAKey is the default ConfigMap key holding the object storage CA certificate
+	DefaultCAKey = "service-ca.crt"
+	// DefaultSTSAudience is the default audience of the projected service account token used for AWS STS
+	DefaultSTSAudience = "sts.amazonaws.com"
+
+	secretDirectory = "/etc/storage/secrets"
+	caDirectory     = "/var/run/tls/storage"
+	caVolumeName    = "storage-tls"
+	expandEnvArg    = "-config.expand-env=true"
+
+	saTokenDirectory         = "/var/run/secrets/storage/serviceaccount"
+	saTokenVolumeName        = "bound-sa-token"
+	saTokenFileName          = "token"
+	saTokenExpirationSeconds = int64(3600)
+
+	// Keys of the object storage secret referenced by the credential env vars
+	keyAWSAccessKeyID     = "access_key_id"
+	keyAWSAccessKeySecret = "access_key_secret"
+	keyAzureAccountKey    = "account_key"
+	keySwiftPassword      = "password"
+)
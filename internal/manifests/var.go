@@ -15,6 +15,9 @@ const (
 	grpcPort    = 9095
 	protocolTCP = "TCP"
 
+	memcachedPort     = 11211
+	memcachedPortName = "client"
+
 	lokiHTTPPortName   = "metrics"
 	lokiGRPCPortName   = "grpc"
 	lokiGossipPortName = "gossip-ring"
@@ -25,17 +28,28 @@ const (
 	gatewayHTTPPortName     = "public"
 	gatewayInternalPortName = "metrics"
 
+	// LokiHTTPPort is the port of the HTTP server of all Loki components.
+	LokiHTTPPort = httpPort
+
+	// StorageVolumeClaimName is the name of the volume claim template of the Loki component statefulsets.
+	StorageVolumeClaimName = storageVolumeName
+
 	// EnvRelatedImageLoki is the environment variable to fetch the Loki image pullspec.
 	EnvRelatedImageLoki = "RELATED_IMAGE_LOKI"
 	// EnvRelatedImageGateway is the environment variable to fetch the Gateway image pullspec.
 	EnvRelatedImageGateway = "RELATED_IMAGE_GATEWAY"
+	// EnvRelatedImageMemcached is the environment variable to fetch the memcached image pullspec.
+	EnvRelatedImageMemcached = "RELATED_IMAGE_MEMCACHED"
 
 	// DefaultContainerImage declares the default fallback for loki image.
-	DefaultContainerImage = "docker.io/grafana/loki:2.2.1"
+	DefaultContainerImage = "docker.io/grafana/loki:2.4.2"
 
 	// DefaultLokiStackGatewayImage declares the default image for lokiStack-gateway.
 	DefaultLokiStackGatewayImage = "quay.io/observatorium/api:latest"
 
+	// DefaultMemcachedImage declares the default image for managed memcached caches.
+	DefaultMemcachedImage = "docker.io/library/memcached:1.6.12-alpine"
+
 	// PrometheusCAFile declares the path for prometheus CA file for service monitors.
 	PrometheusCAFile string = "/etc/prometheus/configmaps/serving-certs-ca-bundle/service-ca.crt"
 	// BearerTokenFile declares the path for bearer token file for service monitors.
@@ -44,6 +58,11 @@ const (
 	// labelJobComponent is a ServiceMonitor.Spec.JobLabel.
 	labelJobComponent string = "loki.grafana.com/component"
 
+	// LabelStackName is the label key for the name of the LokiStack owning an object
+	LabelStackName string = "loki.grafana.com/name"
+	// LabelComponent is the label key for the component of an object
+	LabelComponent string = "loki.grafana.com/component"
+
 	// LabelCompactorComponent is the label value for the compactor component
 	LabelCompactorComponent string = "compactor"
 	// LabelDistributorComponent is the label value for the distributor component
@@ -56,6 +75,10 @@ const (
 	LabelQueryFrontendComponent string = "query-frontend"
 	// LabelGatewayComponent is the label value for the lokiStack-gateway component
 	LabelGatewayComponent string = "lokistack-gateway"
+	// LabelRulerComponent is the label value for the ruler component
+	LabelRulerComponent string = "ruler"
+	// LabelIndexGatewayComponent is the label value for the index gateway component
+	LabelIndexGatewayComponent string = "index-gateway"
 )
 
 var (
@@ -63,17 +86,23 @@ var (
 	volumeFileSystemMode = corev1.PersistentVolumeFilesystem
 )
 
-func commonAnnotations(h string) map[string]string {
-	return map[string]string{
-		"loki.openshift.io/config-hash": h,
+func commonAnnotations(configHash, objStoreHash string) map[string]string {
+	a := map[string]string{
+		"loki.openshift.io/config-hash": configHash,
+	}
+
+	if objStoreHash != "" {
+		a["loki.openshift.io/object-storage-secret-hash"] = objStoreHash
 	}
+
+	return a
 }
 
 func commonLabels(stackName string) map[string]string {
 	return map[string]string{
 		"app.kubernetes.io/name":     "loki",
 		"app.kubernetes.io/provider": "openshift",
-		"loki.grafana.com/name":      stackName,
+		LabelStackName:               stackName,
 	}
 }
 
@@ -88,7 +117,7 @@ func serviceAnnotations(serviceName string, enableSigningService bool) map[strin
 // ComponentLabels is a list of all commonLabels including the loki.grafana.com/component:<component> label
 func ComponentLabels(component, stackName string) labels.Set {
 	return labels.Merge(commonLabels(stackName), map[string]string{
-		"loki.grafana.com/component": component,
+		LabelComponent: component,
 	})
 }
 
@@ -124,11 +153,46 @@ func QueryFrontendName(stackName string) string {
 	return fmt.Sprintf("loki-query-frontend-%s", stackName)
 }
 
+// RulerName is the name of the ruler statefulset
+func RulerName(stackName string) string {
+	return fmt.Sprintf("loki-ruler-%s", stackName)
+}
+
+// RulesConfigMapName is the name of the alerting and recording rules configmap
+func RulesConfigMapName(stackName string) string {
+	return fmt.Sprintf("loki-rules-%s", stackName)
+}
+
+// IndexGatewayName is the name of the index gateway statefulset
+func IndexGatewayName(stackName string) string {
+	return fmt.Sprintf("loki-index-gateway-%s", stackName)
+}
+
+// MemcachedName is the name of the memcached statefulset of the given cache
+func MemcachedName(stackName, cache string) string {
+	return fmt.Sprintf("loki-memcached-%s-%s", cache, stackName)
+}
+
 // GatewayName is the name of the lokiStack-gateway statefulset
 func GatewayName(stackName string) string {
 	return fmt.Sprintf("lokistack-gateway-%s", stackName)
 }
 
+// ServiceAccountName is the name of the ServiceAccount used by the Loki components
+func ServiceAccountName(stackName string) string {
+	return fmt.Sprintf("loki-%s", stackName)
+}
+
+// IngesterHTTPServiceName is the name of the ingester HTTP service
+func IngesterHTTPServiceName(stackName string) string {
+	return serviceNameIngesterHTTP(stackName)
+}
+
+// DistributorHTTPServiceName is the name of the distributor HTTP service
+func DistributorHTTPServiceName(stackName string) string {
+	return serviceNameDistributorHTTP(stackName)
+}
+
 func serviceNameQuerierHTTP(stackName string) string {
 	return fmt.Sprintf("loki-querier-http-%s", stackName)
 }
@@ -169,6 +233,26 @@ func serviceNameQueryFrontendHTTP(stackName string) string {
 	return fmt.Sprintf("loki-query-frontend-http-%s", stackName)
 }
 
+func serviceNameRulerGRPC(stackName string) string {
+	return fmt.Sprintf("loki-ruler-grpc-%s", stackName)
+}
+
+func serviceNameRulerHTTP(stackName string) string {
+	return fmt.Sprintf("loki-ruler-http-%s", stackName)
+}
+
+func serviceNameIndexGatewayGRPC(stackName string) string {
+	return fmt.Sprintf("loki-index-gateway-grpc-%s", stackName)
+}
+
+func serviceNameIndexGatewayHTTP(stackName string) string {
+	return fmt.Sprintf("loki-index-gateway-http-%s", stackName)
+}
+
+func serviceNameMemcached(stackName, cache string) string {
+	return fmt.Sprintf("loki-memcached-%s-%s", cache, stackName)
+}
+
 func serviceNameGatewayHTTP(stackName string) string {
 	return fmt.Sprintf("lokistack-gateway-http-%s", stackName)
 }
@@ -0,0 +1,121 @@
+package manifests
+
+import (
+	"fmt"
+	"path"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// AnnotationAvailabilityZone is the ingester pod annotation holding the
+	// availability zone of the node the pod is scheduled on.
+	AnnotationAvailabilityZone = "loki.openshift.io/availability-zone"
+
+	availabilityZoneEnvVarName = "INSTANCE_AVAILABILITY_ZONE"
+	availabilityZoneVolumeName = "availability-zone"
+	availabilityZoneMountDir   = "/etc/loki/availability-zone"
+	availabilityZoneFileName   = "zone"
+)
+
+// configureZoneAwareness spreads the pods selected by the given labels across
+// the zones defined by the topology keys.
+func configureZoneAwareness(podSpec *corev1.PodSpec, l labels.Set, spec *lokiv1beta1.ZoneAwarenessSpec) {
+	if spec == nil {
+		return
+	}
+
+	var terms []corev1.WeightedPodAffinityTerm
+	for _, key := range spec.TopologyKeys {
+		podSpec.TopologySpreadConstraints = append(podSpec.TopologySpreadConstraints, corev1.TopologySpreadConstraint{
+			MaxSkew:           1,
+			TopologyKey:       key,
+			WhenUnsatisfiable: corev1.ScheduleAnyway,
+			LabelSelector: &metav1.LabelSelector{
+				MatchLabels: l,
+			},
+		})
+
+		terms = append(terms, corev1.WeightedPodAffinityTerm{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				TopologyKey: key,
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: l,
+				},
+			},
+		})
+	}
+
+	// Keep the affinity defined in the component template.
+	if podSpec.Affinity == nil {
+		podSpec.Affinity = &corev1.Affinity{}
+	}
+	if podSpec.Affinity.PodAntiAffinity == nil {
+		podSpec.Affinity.PodAntiAffinity = &corev1.PodAntiAffinity{}
+	}
+
+	anti := podSpec.Affinity.PodAntiAffinity
+	anti.PreferredDuringSchedulingIgnoredDuringExecution = append(anti.PreferredDuringSchedulingIgnoredDuringExecution, terms...)
+}
+
+// configureIngesterAvailabilityZone passes the availability zone annotated on the
+// ingester pods by the operator to the ingester container. An init container holds
+// back the ingester until the annotation is available via the downward API.
+func configureIngesterAvailabilityZone(sts *appsv1.StatefulSet, image string) {
+	fieldPath := fmt.Sprintf("metadata.annotations['%s']", AnnotationAvailabilityZone)
+	zoneFile := path.Join(availabilityZoneMountDir, availabilityZoneFileName)
+
+	podSpec := &sts.Spec.Template.Spec
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: availabilityZoneVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			DownwardAPI: &corev1.DownwardAPIVolumeSource{
+				Items: []corev1.DownwardAPIVolumeFile{
+					{
+						Path: availabilityZoneFileName,
+						FieldRef: &corev1.ObjectFieldSelector{
+							FieldPath: fieldPath,
+						},
+					},
+				},
+			},
+		},
+	})
+
+	podSpec.InitContainers = append(podSpec.InitContainers, corev1.Container{
+		Image:           image,
+		Name:            "wait-for-availability-zone",
+		SecurityContext: podSpec.Containers[0].SecurityContext,
+		Command: []string{
+			"sh",
+			"-c",
+			fmt.Sprintf("until test -s %s; do echo waiting for availability zone annotation; sleep 5; done", zoneFile),
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      availabilityZoneVolumeName,
+				ReadOnly:  true,
+				MountPath: availabilityZoneMountDir,
+			},
+		},
+		TerminationMessagePath:   "/dev/termination-log",
+		TerminationMessagePolicy: "File",
+		ImagePullPolicy:          "IfNotPresent",
+	})
+
+	ingester := &podSpec.Containers[0]
+	ingester.Env = append(ingester.Env, corev1.EnvVar{
+		Name: availabilityZoneEnvVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				FieldPath: fieldPath,
+			},
+		},
+	})
+	ingester.Args = append(ingester.Args, fmt.Sprintf("-ingester.availability-zone=$(%s)", availabilityZoneEnvVarName))
+}
@@ -0,0 +1,93 @@
+package manifests
+
+import (
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestConfigureZoneAwareness_SpreadsPodsAcrossTopologyKeys(t *testing.T) {
+	opts := Options{
+		Name:      "abcd",
+		Namespace: "efgh",
+		Stack: lokiv1beta1.LokiStackSpec{
+			ZoneAwareness: &lokiv1beta1.ZoneAwarenessSpec{
+				TopologyKeys: []string{"topology.kubernetes.io/zone", "kubernetes.io/hostname"},
+			},
+			Template: &lokiv1beta1.LokiTemplateSpec{
+				Distributor: &lokiv1beta1.LokiComponentSpec{
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	podSpec := NewDistributorDeployment(opts).Spec.Template.Spec
+	l := map[string]string(ComponentLabels(LabelDistributorComponent, opts.Name))
+
+	require.Len(t, podSpec.TopologySpreadConstraints, 2)
+	require.Len(t, podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution, 2)
+
+	for i, key := range opts.Stack.ZoneAwareness.TopologyKeys {
+		c := podSpec.TopologySpreadConstraints[i]
+		require.Equal(t, key, c.TopologyKey)
+		require.Equal(t, l, c.LabelSelector.MatchLabels)
+
+		term := podSpec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution[i]
+		require.Equal(t, key, term.PodAffinityTerm.TopologyKey)
+		require.Equal(t, l, term.PodAffinityTerm.LabelSelector.MatchLabels)
+	}
+}
+
+func TestConfigureZoneAwareness_Disabled(t *testing.T) {
+	podSpec := corev1.PodSpec{}
+	configureZoneAwareness(&podSpec, ComponentLabels(LabelQuerierComponent, "abcd"), nil)
+
+	require.Empty(t, podSpec.TopologySpreadConstraints)
+	require.Nil(t, podSpec.Affinity)
+}
+
+func TestConfigureIngesterAvailabilityZone(t *testing.T) {
+	sts := &appsv1.StatefulSet{
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "loki-ingester",
+							Args: []string{"-target=ingester"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	configureIngesterAvailabilityZone(sts, "loki:latest")
+
+	podSpec := sts.Spec.Template.Spec
+	fieldPath := "metadata.annotations['loki.openshift.io/availability-zone']"
+
+	require.Len(t, podSpec.Volumes, 1)
+	require.Equal(t, fieldPath, podSpec.Volumes[0].DownwardAPI.Items[0].FieldRef.FieldPath)
+
+	require.Len(t, podSpec.InitContainers, 1)
+	require.Equal(t, "loki:latest", podSpec.InitContainers[0].Image)
+	require.Equal(t, availabilityZoneMountDir, podSpec.InitContainers[0].VolumeMounts[0].MountPath)
+
+	ingester := podSpec.Containers[0]
+	require.Contains(t, ingester.Args, "-ingester.availability-zone=$(INSTANCE_AVAILABILITY_ZONE)")
+	require.Equal(t, []corev1.EnvVar{
+		{
+			Name: "INSTANCE_AVAILABILITY_ZONE",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fieldPath,
+				},
+			},
+		},
+	}, ingester.Env)
+}
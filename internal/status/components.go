@@ -2,12 +2,14 @@ package status
 
 import (
 	"context"
+	"sort"
 
 	"github.com/ViaQ/logerr/kverrors"
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s"
 	"github.com/ViaQ/loki-operator/internal/manifests"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -55,6 +57,21 @@ func SetComponentsStatus(ctx context.Context, k k8s.Client, req ctrl.Request) er
 	if err != nil {
 		return kverrors.Wrap(err, "failed lookup LokiStack component pods status", "name", manifests.LabelGatewayComponent)
 	}
+
+	s.Status.Components.Ruler, err = appendPodStatus(ctx, k, manifests.LabelRulerComponent, s.Name, s.Namespace)
+	if err != nil {
+		return kverrors.Wrap(err, "failed lookup LokiStack component pods status", "name", manifests.LabelRulerComponent)
+	}
+
+	s.Status.Components.IndexGateway, err = appendPodStatus(ctx, k, manifests.LabelIndexGatewayComponent, s.Name, s.Namespace)
+	if err != nil {
+		return kverrors.Wrap(err, "failed lookup LokiStack component pods status", "name", manifests.LabelIndexGatewayComponent)
+	}
+
+	s.Status.Components.Replicas, err = componentReplicas(ctx, k, s.Name, s.Namespace)
+	if err != nil {
+		return kverrors.Wrap(err, "failed lookup LokiStack component replicas", "name", req.NamespacedName)
+	}
 	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
 }
 
@@ -74,3 +91,56 @@ func appendPodStatus(ctx context.Context, k k8s.Client, component, stack, ns str
 	}
 	return psm, nil
 }
+
+// componentReplicas sums the ready and desired replicas of all deployments
+// and statefulsets of the stack per component.
+func componentReplicas(ctx context.Context, k k8s.Client, stack, ns string) ([]lokiv1beta1.ComponentReplicasStatus, error) {
+	opts := []client.ListOption{
+		client.MatchingLabels{manifests.LabelStackName: stack},
+		client.InNamespace(ns),
+	}
+
+	replicas := map[string]*lokiv1beta1.ComponentReplicasStatus{}
+	add := func(labels map[string]string, desired *int32, ready int32) {
+		component := labels[manifests.LabelComponent]
+		r, ok := replicas[component]
+		if !ok {
+			r = &lokiv1beta1.ComponentReplicasStatus{Component: component}
+			replicas[component] = r
+		}
+
+		// Defaulted to 1 by the API server if unset.
+		if desired == nil {
+			r.Desired++
+		} else {
+			r.Desired += *desired
+		}
+		r.Ready += ready
+	}
+
+	deployments := &appsv1.DeploymentList{}
+	if err := k.List(ctx, deployments, opts...); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list deployments for LokiStack", "name", stack)
+	}
+	for _, d := range deployments.Items {
+		add(d.Labels, d.Spec.Replicas, d.Status.ReadyReplicas)
+	}
+
+	statefulSets := &appsv1.StatefulSetList{}
+	if err := k.List(ctx, statefulSets, opts...); err != nil {
+		return nil, kverrors.Wrap(err, "failed to list statefulsets for LokiStack", "name", stack)
+	}
+	for _, sts := range statefulSets.Items {
+		add(sts.Labels, sts.Spec.Replicas, sts.Status.ReadyReplicas)
+	}
+
+	var res []lokiv1beta1.ComponentReplicasStatus
+	for _, r := range replicas {
+		res = append(res, *r)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Component < res[j].Component
+	})
+
+	return res, nil
+}
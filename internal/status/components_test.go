@@ -6,14 +6,17 @@ import (
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/manifests"
 	"github.com/ViaQ/loki-operator/internal/status"
 	"github.com/stretchr/testify/require"
 
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -119,6 +122,10 @@ func TestSetComponentsStatus_WhenPodListExisting_SetPodStatusMap(t *testing.T) {
 	}
 
 	k.ListStub = func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+		if _, ok := l.(*v1.PodList); !ok {
+			return nil
+		}
+
 		pods := v1.PodList{
 			Items: []v1.Pod{
 				{
@@ -160,3 +167,87 @@ func TestSetComponentsStatus_WhenPodListExisting_SetPodStatusMap(t *testing.T) {
 	require.NotZero(t, k.StatusCallCount())
 	require.NotZero(t, sw.UpdateCallCount())
 }
+
+func TestSetComponentsStatus_WhenWorkloadsExisting_SetReplicas(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	s := lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &s)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+
+	k.ListStub = func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+		switch l.(type) {
+		case *appsv1.DeploymentList:
+			k.SetClientObjectList(l, &appsv1.DeploymentList{
+				Items: []appsv1.Deployment{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "loki-distributor-my-stack",
+							Labels: manifests.ComponentLabels(manifests.LabelDistributorComponent, "my-stack"),
+						},
+						Spec:   appsv1.DeploymentSpec{Replicas: pointer.Int32Ptr(2)},
+						Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+					},
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "loki-gateway-my-stack",
+							Labels: manifests.ComponentLabels(manifests.LabelGatewayComponent, "my-stack"),
+						},
+						Status: appsv1.DeploymentStatus{ReadyReplicas: 1},
+					},
+				},
+			})
+		case *appsv1.StatefulSetList:
+			k.SetClientObjectList(l, &appsv1.StatefulSetList{
+				Items: []appsv1.StatefulSet{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "loki-ingester-my-stack",
+							Labels: manifests.ComponentLabels(manifests.LabelIngesterComponent, "my-stack"),
+						},
+						Spec:   appsv1.StatefulSetSpec{Replicas: pointer.Int32Ptr(3)},
+						Status: appsv1.StatefulSetStatus{ReadyReplicas: 3},
+					},
+				},
+			})
+		}
+		return nil
+	}
+
+	expected := []lokiv1beta1.ComponentReplicasStatus{
+		{Component: manifests.LabelDistributorComponent, Ready: 1, Desired: 2},
+		{Component: manifests.LabelIngesterComponent, Ready: 3, Desired: 3},
+		{Component: manifests.LabelGatewayComponent, Ready: 1, Desired: 1},
+	}
+
+	sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		stack := obj.(*lokiv1beta1.LokiStack)
+		require.Equal(t, expected, stack.Status.Components.Replicas)
+		return nil
+	}
+
+	err := status.SetComponentsStatus(context.TODO(), k, r)
+	require.NoError(t, err)
+	require.NotZero(t, sw.UpdateCallCount())
+}
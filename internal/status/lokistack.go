@@ -7,8 +7,10 @@ import (
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/internal/external/k8s"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -152,7 +154,8 @@ func SetPendingCondition(ctx context.Context, k k8s.Client, req ctrl.Request) er
 }
 
 // SetDegradedCondition appends the condition Degraded to the lokistack status conditions.
-func SetDegradedCondition(ctx context.Context, k k8s.Client, req ctrl.Request, msg string, reason lokiv1beta1.LokiStackConditionReason) error {
+// In addition it records a warning event with the same reason and message on the lokistack.
+func SetDegradedCondition(ctx context.Context, k k8s.Client, rec record.EventRecorder, req ctrl.Request, msg string, reason lokiv1beta1.LokiStackConditionReason) error {
 	var s lokiv1beta1.LokiStack
 	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -194,5 +197,10 @@ func SetDegradedCondition(ctx context.Context, k k8s.Client, req ctrl.Request, m
 		s.Status.Conditions[index] = degraded
 	}
 
-	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
+	if err := k.Status().Update(ctx, &s, &client.UpdateOptions{}); err != nil {
+		return err
+	}
+
+	rec.Event(&s, corev1.EventTypeWarning, reasonStr, msg)
+	return nil
 }
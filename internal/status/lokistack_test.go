@@ -14,6 +14,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -365,7 +366,7 @@ func TestSetDegradedCondition_WhenGetLokiStackReturnsError_ReturnError(t *testin
 		return apierrors.NewBadRequest("something wasn't found")
 	}
 
-	err := status.SetDegradedCondition(context.TODO(), k, r, msg, reason)
+	err := status.SetDegradedCondition(context.TODO(), k, &record.FakeRecorder{}, r, msg, reason)
 	require.Error(t, err)
 }
 
@@ -550,7 +551,7 @@ func TestSetDegradedCondition_WhenGetLokiStackReturnsNotFound_DoNothing(t *testi
 		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
 	}
 
-	err := status.SetDegradedCondition(context.TODO(), k, r, msg, reason)
+	err := status.SetDegradedCondition(context.TODO(), k, &record.FakeRecorder{}, r, msg, reason)
 	require.NoError(t, err)
 }
 
@@ -590,7 +591,7 @@ func TestSetDegradedCondition_WhenExisting_DoNothing(t *testing.T) {
 		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
 	}
 
-	err := status.SetDegradedCondition(context.TODO(), k, r, msg, reason)
+	err := status.SetDegradedCondition(context.TODO(), k, &record.FakeRecorder{}, r, msg, reason)
 	require.NoError(t, err)
 	require.Zero(t, k.StatusCallCount())
 }
@@ -641,7 +642,7 @@ func TestSetDegradedCondition_WhenExisting_SetDegradedConditionTrue(t *testing.T
 		return nil
 	}
 
-	err := status.SetDegradedCondition(context.TODO(), k, r, msg, reason)
+	err := status.SetDegradedCondition(context.TODO(), k, &record.FakeRecorder{}, r, msg, reason)
 	require.NoError(t, err)
 	require.NotZero(t, k.StatusCallCount())
 	require.NotZero(t, sw.UpdateCallCount())
@@ -683,9 +684,11 @@ func TestSetDegradedCondition_WhenNoneExisting_AppendDegradedCondition(t *testin
 		return nil
 	}
 
-	err := status.SetDegradedCondition(context.TODO(), k, r, msg, reason)
+	rec := record.NewFakeRecorder(1)
+	err := status.SetDegradedCondition(context.TODO(), k, rec, r, msg, reason)
 	require.NoError(t, err)
 
 	require.NotZero(t, k.StatusCallCount())
 	require.NotZero(t, sw.UpdateCallCount())
+	require.Equal(t, "Warning MissingObjectStorageSecret tell me something", <-rec.Events)
 }
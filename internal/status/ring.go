@@ -0,0 +1,33 @@
+package status
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetRingStatus updates the ingester ring status
+func SetRingStatus(ctx context.Context, k k8s.Client, req ctrl.Request, ring *lokiv1beta1.RingStatus) error {
+	var s lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	if equality.Semantic.DeepEqual(s.Status.Ring, ring) {
+		return nil
+	}
+
+	s.Status.Ring = ring
+
+	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
+}
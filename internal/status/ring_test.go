@@ -0,0 +1,95 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetRingStatus(t *testing.T) {
+	current := &lokiv1beta1.RingStatus{
+		Ingesters: []lokiv1beta1.RingMemberStatus{
+			{Name: "loki-ingester-my-stack-0", State: "ACTIVE", Tokens: 512, TokenOwnership: "50.00%"},
+			{Name: "loki-ingester-my-stack-1", State: "ACTIVE", Tokens: 512, TokenOwnership: "50.00%"},
+		},
+	}
+
+	type test struct {
+		name       string
+		ring       *lokiv1beta1.RingStatus
+		wantUpdate bool
+	}
+	table := []test{
+		{
+			name: "unchanged",
+			ring: current.DeepCopy(),
+		},
+		{
+			name: "changed state",
+			ring: &lokiv1beta1.RingStatus{
+				Ingesters: []lokiv1beta1.RingMemberStatus{
+					{Name: "loki-ingester-my-stack-0", State: "ACTIVE", Tokens: 512, TokenOwnership: "50.00%"},
+					{Name: "loki-ingester-my-stack-1", State: "UNHEALTHY", Tokens: 512, TokenOwnership: "50.00%"},
+				},
+			},
+			wantUpdate: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			sw := &k8sfakes.FakeStatusWriter{}
+			k := &k8sfakes.FakeClient{}
+			k.StatusStub = func() client.StatusWriter { return sw }
+
+			s := lokiv1beta1.LokiStack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+				Status: lokiv1beta1.LokiStackStatus{
+					Ring: current.DeepCopy(),
+				},
+			}
+
+			r := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+			}
+
+			k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+				k.SetClientObject(object, &s)
+				return nil
+			}
+
+			sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				stack := obj.(*lokiv1beta1.LokiStack)
+				require.Equal(t, tst.ring, stack.Status.Ring)
+				return nil
+			}
+
+			err := status.SetRingStatus(context.TODO(), k, r, tst.ring)
+			require.NoError(t, err)
+
+			if tst.wantUpdate {
+				require.Equal(t, 1, sw.UpdateCallCount())
+			} else {
+				require.Zero(t, sw.UpdateCallCount())
+			}
+		})
+	}
+}
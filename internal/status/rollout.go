@@ -0,0 +1,33 @@
+package status
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetIngesterRolloutStatus updates the ingester rollout status
+func SetIngesterRolloutStatus(ctx context.Context, k k8s.Client, req ctrl.Request, rollout *lokiv1beta1.RolloutStatus) error {
+	var s lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	if equality.Semantic.DeepEqual(s.Status.IngesterRollout, rollout) {
+		return nil
+	}
+
+	s.Status.IngesterRollout = rollout
+
+	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
+}
@@ -0,0 +1,117 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetIngesterRolloutStatus_WhenGetLokiStackReturnsNotFound_DoNothing(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+
+	err := status.SetIngesterRolloutStatus(context.TODO(), k, r, &lokiv1beta1.RolloutStatus{})
+	require.NoError(t, err)
+}
+
+func TestSetIngesterRolloutStatus(t *testing.T) {
+	current := &lokiv1beta1.RolloutStatus{
+		Revision:        "rev-2",
+		Replicas:        3,
+		UpdatedReplicas: 1,
+		Pod:             "loki-ingester-my-stack-2",
+		Phase:           lokiv1beta1.RolloutPhaseFlushing,
+	}
+
+	type test struct {
+		name       string
+		rollout    *lokiv1beta1.RolloutStatus
+		wantUpdate bool
+	}
+	table := []test{
+		{
+			name:    "unchanged",
+			rollout: current.DeepCopy(),
+		},
+		{
+			name: "changed phase",
+			rollout: &lokiv1beta1.RolloutStatus{
+				Revision:        "rev-2",
+				Replicas:        3,
+				UpdatedReplicas: 1,
+				Pod:             "loki-ingester-my-stack-2",
+				Phase:           lokiv1beta1.RolloutPhaseLeaving,
+			},
+			wantUpdate: true,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			sw := &k8sfakes.FakeStatusWriter{}
+			k := &k8sfakes.FakeClient{}
+			k.StatusStub = func() client.StatusWriter { return sw }
+
+			s := lokiv1beta1.LokiStack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+				Status: lokiv1beta1.LokiStackStatus{
+					IngesterRollout: current.DeepCopy(),
+				},
+			}
+
+			r := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+			}
+
+			k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+				k.SetClientObject(object, &s)
+				return nil
+			}
+
+			sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				stack := obj.(*lokiv1beta1.LokiStack)
+				require.Equal(t, tst.rollout, stack.Status.IngesterRollout)
+				return nil
+			}
+
+			err := status.SetIngesterRolloutStatus(context.TODO(), k, r, tst.rollout)
+			require.NoError(t, err)
+
+			if tst.wantUpdate {
+				require.Equal(t, 1, sw.UpdateCallCount())
+			} else {
+				require.Zero(t, sw.UpdateCallCount())
+			}
+		})
+	}
+}
@@ -13,13 +13,19 @@ import (
 )
 
 // Refresh executes an aggregate update of the LokiStack Status struct, i.e.
-// - It recreates the Status.Components pod status map per component.
-// - It sets the appropriate Status.Condition to true that matches the pod status maps.
-func Refresh(ctx context.Context, k k8s.Client, req ctrl.Request) error {
+// - It recreates the Status.Components pod status map and replicas per component.
+// - It records the persistent volume claims still being expanded in Status.Storage.
+// - It sets the appropriate Status.Condition to true that matches the component status.
+// - It keeps the Degraded condition of a degraded lokistack, e.g. with an unhealthy ingester ring.
+func Refresh(ctx context.Context, k k8s.Client, req ctrl.Request, degraded bool) error {
 	if err := SetComponentsStatus(ctx, k, req); err != nil {
 		return err
 	}
 
+	if err := SetVolumeExpansionStatus(ctx, k, req); err != nil {
+		return err
+	}
+
 	var s lokiv1beta1.LokiStack
 	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
 		if apierrors.IsNotFound(err) {
@@ -28,6 +34,10 @@ func Refresh(ctx context.Context, k k8s.Client, req ctrl.Request) error {
 		return kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
 	}
 
+	if degraded {
+		return nil
+	}
+
 	cs := s.Status.Components
 
 	// Check for failed pods first
@@ -35,27 +45,43 @@ func Refresh(ctx context.Context, k k8s.Client, req ctrl.Request) error {
 		len(cs.Distributor[corev1.PodFailed]) +
 		len(cs.Ingester[corev1.PodFailed]) +
 		len(cs.Querier[corev1.PodFailed]) +
-		len(cs.QueryFrontend[corev1.PodFailed])
+		len(cs.QueryFrontend[corev1.PodFailed]) +
+		len(cs.Gateway[corev1.PodFailed]) +
+		len(cs.Ruler[corev1.PodFailed]) +
+		len(cs.IndexGateway[corev1.PodFailed])
 
 	unknown := len(cs.Compactor[corev1.PodUnknown]) +
 		len(cs.Distributor[corev1.PodUnknown]) +
 		len(cs.Ingester[corev1.PodUnknown]) +
 		len(cs.Querier[corev1.PodUnknown]) +
-		len(cs.QueryFrontend[corev1.PodUnknown])
+		len(cs.QueryFrontend[corev1.PodUnknown]) +
+		len(cs.Gateway[corev1.PodUnknown]) +
+		len(cs.Ruler[corev1.PodUnknown]) +
+		len(cs.IndexGateway[corev1.PodUnknown])
 
 	if failed != 0 || unknown != 0 {
 		return SetFailedCondition(ctx, k, req)
 	}
 
-	// Check for pending pods
-	pending := len(cs.Compactor[corev1.PodPending]) +
-		len(cs.Distributor[corev1.PodPending]) +
-		len(cs.Ingester[corev1.PodPending]) +
-		len(cs.Querier[corev1.PodPending]) +
-		len(cs.QueryFrontend[corev1.PodPending])
-
-	if pending != 0 {
+	// Running pods may still fail their readiness probes,
+	// so compare the ready with the desired replicas.
+	if !replicasReady(cs.Replicas) {
 		return SetPendingCondition(ctx, k, req)
 	}
 	return SetReadyCondition(ctx, k, req)
 }
+
+// replicasReady returns true if all components have their desired replicas ready.
+// A stack without any deployed component is not ready yet.
+func replicasReady(replicas []lokiv1beta1.ComponentReplicasStatus) bool {
+	if len(replicas) == 0 {
+		return false
+	}
+
+	for _, r := range replicas {
+		if r.Ready < r.Desired {
+			return false
+		}
+	}
+	return true
+}
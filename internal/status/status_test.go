@@ -0,0 +1,188 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+	"github.com/ViaQ/loki-operator/internal/status"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRefresh_SetConditionFromReplicas(t *testing.T) {
+	type test struct {
+		name        string
+		phase       corev1.PodPhase
+		deployments []appsv1.Deployment
+		want        lokiv1beta1.LokiStackConditionType
+	}
+	table := []test{
+		{
+			name:  "all replicas ready",
+			phase: corev1.PodRunning,
+			deployments: []appsv1.Deployment{
+				newDeployment(manifests.LabelDistributorComponent, 2, 2),
+				newDeployment(manifests.LabelGatewayComponent, 1, 1),
+			},
+			want: lokiv1beta1.ConditionReady,
+		},
+		{
+			name:  "running pod not ready",
+			phase: corev1.PodRunning,
+			deployments: []appsv1.Deployment{
+				newDeployment(manifests.LabelDistributorComponent, 2, 2),
+				newDeployment(manifests.LabelGatewayComponent, 1, 0),
+			},
+			want: lokiv1beta1.ConditionPending,
+		},
+		{
+			name:  "failed pod",
+			phase: corev1.PodFailed,
+			deployments: []appsv1.Deployment{
+				newDeployment(manifests.LabelGatewayComponent, 1, 1),
+			},
+			want: lokiv1beta1.ConditionFailed,
+		},
+		{
+			name:  "no components deployed",
+			phase: corev1.PodRunning,
+			want:  lokiv1beta1.ConditionPending,
+		},
+	}
+	for _, tst := range table {
+		tst := tst
+		t.Run(tst.name, func(t *testing.T) {
+			t.Parallel()
+
+			sw := &k8sfakes.FakeStatusWriter{}
+			k := &k8sfakes.FakeClient{}
+			k.StatusStub = func() client.StatusWriter { return sw }
+
+			s := lokiv1beta1.LokiStack{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+			}
+
+			r := ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      "my-stack",
+					Namespace: "some-ns",
+				},
+			}
+
+			k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+				k.SetClientObject(object, &s)
+				return nil
+			}
+
+			k.ListStub = func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+				switch l.(type) {
+				case *corev1.PodList:
+					k.SetClientObjectList(l, &corev1.PodList{
+						Items: []corev1.Pod{
+							{
+								ObjectMeta: metav1.ObjectMeta{Name: "pod-a"},
+								Status:     corev1.PodStatus{Phase: tst.phase},
+							},
+						},
+					})
+				case *appsv1.DeploymentList:
+					k.SetClientObjectList(l, &appsv1.DeploymentList{Items: tst.deployments})
+				}
+				return nil
+			}
+
+			sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+				s = *obj.(*lokiv1beta1.LokiStack).DeepCopy()
+				return nil
+			}
+
+			err := status.Refresh(context.TODO(), k, r, false)
+			require.NoError(t, err)
+
+			require.Len(t, s.Status.Conditions, 1)
+			require.Equal(t, string(tst.want), s.Status.Conditions[0].Type)
+			require.Equal(t, metav1.ConditionTrue, s.Status.Conditions[0].Status)
+		})
+	}
+}
+
+func TestRefresh_WhenDegraded_KeepConditions(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	degraded := metav1.Condition{
+		Type:   string(lokiv1beta1.ConditionDegraded),
+		Status: metav1.ConditionTrue,
+		Reason: string(lokiv1beta1.ReasonUnhealthyRingMembers),
+	}
+
+	s := lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+		Status: lokiv1beta1.LokiStackStatus{
+			Conditions: []metav1.Condition{degraded},
+		},
+	}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+		k.SetClientObject(object, &s)
+		return nil
+	}
+
+	k.ListStub = func(_ context.Context, l client.ObjectList, _ ...client.ListOption) error {
+		if _, ok := l.(*appsv1.DeploymentList); ok {
+			k.SetClientObjectList(l, &appsv1.DeploymentList{
+				Items: []appsv1.Deployment{newDeployment(manifests.LabelDistributorComponent, 1, 1)},
+			})
+		}
+		return nil
+	}
+
+	sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		s = *obj.(*lokiv1beta1.LokiStack).DeepCopy()
+		return nil
+	}
+
+	err := status.Refresh(context.TODO(), k, r, true)
+	require.NoError(t, err)
+
+	require.Equal(t, []metav1.Condition{degraded}, s.Status.Conditions)
+}
+
+func newDeployment(component string, desired, ready int32) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   component,
+			Labels: manifests.ComponentLabels(component, "my-stack"),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(desired),
+		},
+		Status: appsv1.DeploymentStatus{
+			ReadyReplicas: ready,
+		},
+	}
+}
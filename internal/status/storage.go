@@ -0,0 +1,92 @@
+package status
+
+import (
+	"context"
+
+	"github.com/ViaQ/logerr/kverrors"
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s"
+	"github.com/ViaQ/loki-operator/internal/manifests"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SetStorageSchemaStatus updates the storage status component
+func SetStorageSchemaStatus(ctx context.Context, k k8s.Client, req ctrl.Request, schemas []lokiv1beta1.ObjectStorageSchema) error {
+	var s lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	s.Status.Storage.Schemas = schemas
+
+	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
+}
+
+// SetVolumeExpansionStatus updates the status of the persistent volume claims
+// of the LokiStack whose capacity is still lower than the requested storage.
+func SetVolumeExpansionStatus(ctx context.Context, k k8s.Client, req ctrl.Request) error {
+	var s lokiv1beta1.LokiStack
+	if err := k.Get(ctx, req.NamespacedName, &s); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return kverrors.Wrap(err, "failed to lookup lokistack", "name", req.NamespacedName)
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	opts := []client.ListOption{
+		client.MatchingLabels{manifests.LabelStackName: s.Name},
+		client.InNamespace(s.Namespace),
+	}
+	if err := k.List(ctx, pvcs, opts...); err != nil {
+		return kverrors.Wrap(err, "failed to list persistent volume claims for LokiStack", "name", req.NamespacedName)
+	}
+
+	var expansions []lokiv1beta1.VolumeExpansionStatus
+	for _, pvc := range pvcs.Items {
+		requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]
+		if !ok || capacity.Cmp(requested) >= 0 {
+			continue
+		}
+
+		expansions = append(expansions, lokiv1beta1.VolumeExpansionStatus{
+			Name:      pvc.Name,
+			Requested: requested,
+			Capacity:  capacity,
+			Condition: resizeCondition(pvc.Status.Conditions),
+		})
+	}
+
+	s.Status.Storage.VolumeExpansions = expansions
+
+	return k.Status().Update(ctx, &s, &client.UpdateOptions{})
+}
+
+// resizeCondition returns the type of the latest true resize condition.
+func resizeCondition(conds []corev1.PersistentVolumeClaimCondition) string {
+	var latest *corev1.PersistentVolumeClaimCondition
+	for i, c := range conds {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		if c.Type != corev1.PersistentVolumeClaimResizing && c.Type != corev1.PersistentVolumeClaimFileSystemResizePending {
+			continue
+		}
+		if latest == nil || latest.LastTransitionTime.Before(&c.LastTransitionTime) {
+			latest = &conds[i]
+		}
+	}
+
+	if latest == nil {
+		return ""
+	}
+	return string(latest.Type)
+}
@@ -0,0 +1,241 @@
+package status_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
+	"github.com/ViaQ/loki-operator/internal/external/k8s/k8sfakes"
+	"github.com/ViaQ/loki-operator/internal/status"
+
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSetStorageSchemaStatus_WhenGetLokiStackReturnsError_ReturnError(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		return apierrors.NewBadRequest("something wasn't found")
+	}
+
+	err := status.SetStorageSchemaStatus(context.TODO(), k, r, []lokiv1beta1.ObjectStorageSchema{})
+	require.Error(t, err)
+}
+
+func TestSetStorageSchemaStatus_WhenGetLokiStackReturnsNotFound_DoNothing(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+
+	err := status.SetStorageSchemaStatus(context.TODO(), k, r, []lokiv1beta1.ObjectStorageSchema{})
+	require.NoError(t, err)
+}
+
+func TestSetStorageSchemaStatus_WhenSchemasApplied_UpdateStatus(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	s := lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	schemas := []lokiv1beta1.ObjectStorageSchema{
+		{
+			Version:       lokiv1beta1.ObjectStorageSchemaV11,
+			EffectiveDate: "2020-10-01",
+			IndexStore:    lokiv1beta1.ObjectStorageIndexStoreBoltDBShipper,
+			ObjectStore:   lokiv1beta1.ObjectStorageSecretS3,
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &s)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+
+	sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		stack := obj.(*lokiv1beta1.LokiStack)
+		require.Equal(t, schemas, stack.Status.Storage.Schemas)
+		return nil
+	}
+
+	err := status.SetStorageSchemaStatus(context.TODO(), k, r, schemas)
+	require.NoError(t, err)
+
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+}
+
+func TestSetVolumeExpansionStatus_WhenClaimsExpanding_UpdateStatus(t *testing.T) {
+	sw := &k8sfakes.FakeStatusWriter{}
+	k := &k8sfakes.FakeClient{}
+
+	k.StatusStub = func() client.StatusWriter { return sw }
+
+	s := lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	newClaim := func(name, requested, capacity string, conds ...corev1.PersistentVolumeClaimCondition) corev1.PersistentVolumeClaim {
+		return corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "some-ns",
+			},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(requested),
+					},
+				},
+			},
+			Status: corev1.PersistentVolumeClaimStatus{
+				Capacity: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(capacity),
+				},
+				Conditions: conds,
+			},
+		}
+	}
+
+	now := metav1.Now()
+	earlier := metav1.NewTime(now.Add(-time.Minute))
+	pvcs := &corev1.PersistentVolumeClaimList{
+		Items: []corev1.PersistentVolumeClaim{
+			newClaim("storage-loki-ingester-my-stack-0", "20Gi", "20Gi"),
+			newClaim("storage-loki-ingester-my-stack-1", "20Gi", "10Gi",
+				corev1.PersistentVolumeClaimCondition{
+					Type:               corev1.PersistentVolumeClaimResizing,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: earlier,
+				},
+				corev1.PersistentVolumeClaimCondition{
+					Type:               corev1.PersistentVolumeClaimFileSystemResizePending,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: now,
+				},
+			),
+			newClaim("storage-loki-compactor-my-stack-0", "20Gi", "10Gi"),
+		},
+	}
+
+	k.GetStub = func(_ context.Context, name types.NamespacedName, object client.Object) error {
+		if r.Name == name.Name && r.Namespace == name.Namespace {
+			k.SetClientObject(object, &s)
+			return nil
+		}
+		return apierrors.NewNotFound(schema.GroupResource{}, "something wasn't found")
+	}
+
+	k.ListStub = func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+		k.SetClientObjectList(list, pvcs)
+		return nil
+	}
+
+	expected := []lokiv1beta1.VolumeExpansionStatus{
+		{
+			Name:      "storage-loki-ingester-my-stack-1",
+			Requested: resource.MustParse("20Gi"),
+			Capacity:  resource.MustParse("10Gi"),
+			Condition: string(corev1.PersistentVolumeClaimFileSystemResizePending),
+		},
+		{
+			Name:      "storage-loki-compactor-my-stack-0",
+			Requested: resource.MustParse("20Gi"),
+			Capacity:  resource.MustParse("10Gi"),
+		},
+	}
+
+	sw.UpdateStub = func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+		stack := obj.(*lokiv1beta1.LokiStack)
+		require.Equal(t, expected, stack.Status.Storage.VolumeExpansions)
+		return nil
+	}
+
+	err := status.SetVolumeExpansionStatus(context.TODO(), k, r)
+	require.NoError(t, err)
+
+	require.NotZero(t, k.StatusCallCount())
+	require.NotZero(t, sw.UpdateCallCount())
+}
+
+func TestSetVolumeExpansionStatus_WhenListClaimsReturnsError_ReturnError(t *testing.T) {
+	k := &k8sfakes.FakeClient{}
+
+	s := lokiv1beta1.LokiStack{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	r := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      "my-stack",
+			Namespace: "some-ns",
+		},
+	}
+
+	k.GetStub = func(_ context.Context, _ types.NamespacedName, object client.Object) error {
+		k.SetClientObject(object, &s)
+		return nil
+	}
+
+	k.ListStub = func(_ context.Context, _ client.ObjectList, _ ...client.ListOption) error {
+		return apierrors.NewBadRequest("something went wrong")
+	}
+
+	err := status.SetVolumeExpansionStatus(context.TODO(), k, r)
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"time"
 
 	"github.com/ViaQ/logerr/log"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -29,6 +30,8 @@ import (
 
 	lokiv1beta1 "github.com/ViaQ/loki-operator/api/v1beta1"
 	"github.com/ViaQ/loki-operator/controllers"
+	"github.com/ViaQ/loki-operator/internal/external/ingester"
+	"github.com/ViaQ/loki-operator/internal/external/objectstorage"
 	"github.com/ViaQ/loki-operator/internal/manifests"
 	"github.com/ViaQ/loki-operator/internal/metrics"
 	configv1 "github.com/openshift/api/config/v1"
@@ -43,6 +46,10 @@ import (
 	// +kubebuilder:scaffold:imports
 )
 
+// serviceCAFile is mounted into pods on OpenShift. It verifies the serving
+// certificates of the Loki components when TLS is enabled.
+const serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
 var scheme = runtime.NewScheme()
 
 func init() {
@@ -62,6 +69,8 @@ func main() {
 		enableTLSServiceMonitors bool
 		enableGateway            bool
 		enableGatewayRoute       bool
+		storageProbeTimeout      time.Duration
+		ingesterClientTimeout    time.Duration
 	)
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
@@ -78,6 +87,10 @@ func main() {
 		"Enables the manifest creation for the entire lokistack-gateway.")
 	flag.BoolVar(&enableGatewayRoute, "with-lokistack-gateway-route", false,
 		"Enables the usage of Route for the lokistack-gateway instead of Ingress (OCP Only!)")
+	flag.DurationVar(&storageProbeTimeout, "object-storage-probe-timeout", 5*time.Second,
+		"The timeout for probing each object storage bucket before rolling out components.")
+	flag.DurationVar(&ingesterClientTimeout, "ingester-client-timeout", 30*time.Second,
+		"The timeout for calling the flush and shutdown endpoints of ingesters during rollouts.")
 	flag.Parse()
 
 	log.Init("loki-operator")
@@ -116,15 +129,33 @@ func main() {
 		EnableGatewayRoute:              enableGatewayRoute,
 	}
 
+	ingesterClient, err := ingester.NewHTTPClient(ingesterClientTimeout, serviceCAFile)
+	if err != nil {
+		log.Error(err, "unable to create ingester client")
+		os.Exit(1)
+	}
+
 	if err = (&controllers.LokiStackReconciler{
-		Client: mgr.GetClient(),
-		Log:    log.WithName("controllers").WithName("LokiStack"),
-		Scheme: mgr.GetScheme(),
-		Flags:  featureFlags,
+		Client:         mgr.GetClient(),
+		Log:            log.WithName("controllers").WithName("LokiStack"),
+		Scheme:         mgr.GetScheme(),
+		Prober:         objectstorage.NewHTTPProber(storageProbeTimeout),
+		Recorder:       mgr.GetEventRecorderFor("lokistack-controller"),
+		IngesterClient: ingesterClient,
+		Flags:          featureFlags,
 	}).SetupWithManager(mgr); err != nil {
 		log.Error(err, "unable to create controller", "controller", "LokiStack")
 		os.Exit(1)
 	}
+	if err = (&controllers.LokiStackZoneAwarePodReconciler{
+		Client:   mgr.GetClient(),
+		Log:      log.WithName("controllers").WithName("LokiStackZoneAwarePod"),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("lokistack-zone-aware-pod-controller"),
+	}).SetupWithManager(mgr); err != nil {
+		log.Error(err, "unable to create controller", "controller", "LokiStackZoneAwarePod")
+		os.Exit(1)
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err = mgr.AddHealthzCheck("health", healthz.Ping); err != nil {